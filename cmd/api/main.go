@@ -16,6 +16,7 @@ import (
 func main() {
 	// Parse command-line flags
 	portFlag := flag.String("port", "", "Port to run the server on")
+	adminTokenFlag := flag.String("admin-token", "", "Token moderators send to use the moderation endpoints")
 	flag.Parse()
 
 	// Initialize database
@@ -25,6 +26,15 @@ func main() {
 	}
 	defer db.Close()
 
+	// Moderation token: command-line flag > environment variable
+	adminToken := *adminTokenFlag
+	if adminToken == "" {
+		adminToken = os.Getenv("ADMIN_TOKEN")
+	}
+	if adminToken == "" {
+		log.Printf("No admin token set; moderation endpoints are disabled")
+	}
+
 	// Create router
 	router := gin.Default()
 
@@ -32,8 +42,8 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-None-Match", "If-Modified-Since"},
+		ExposeHeaders:    []string{"Content-Length", "ETag", "Last-Modified", "Cache-Control"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -47,6 +57,10 @@ func main() {
 	vocabularyHandler := handlers.NewVocabularyHandler(db)
 	reviewHandler := handlers.NewReviewHandler(db)
 	authHandler := handlers.NewAuthHandler(db, sessionStore)
+	compareHandler := handlers.NewCompareHandler(db)
+	moderationHandler := handlers.NewModerationHandler(db)
+	sessionHandler := handlers.NewSessionHandler(db)
+	quizHandler := handlers.NewQuizHandler(db)
 
 	// API routes
 	api := router.Group("/api")
@@ -54,6 +68,8 @@ func main() {
 		// Public routes
 		// Phase 1: Word lookup (public)
 		api.GET("/words/:word", wordHandler.GetWord)
+		api.GET("/words/:word/examples", wordHandler.GetExamples)
+		api.GET("/compare", compareHandler.CompareWords)
 
 		// Authentication routes (public)
 		api.POST("/auth/login", authHandler.Login)
@@ -69,16 +85,56 @@ func main() {
 			// User management
 			protected.GET("/user", userHandler.GetUser)
 			protected.GET("/user/stats", userHandler.GetUserStats)
+			protected.GET("/user/scheduler", userHandler.GetScheduler)
+			protected.PUT("/user/scheduler", userHandler.SetScheduler)
+			protected.GET("/user/learning-steps", userHandler.GetLearningSteps)
+			protected.PUT("/user/learning-steps", userHandler.SetLearningSteps)
+			protected.GET("/user/daily-limits", userHandler.GetDailyLimits)
+			protected.PUT("/user/daily-limits", userHandler.SetDailyLimits)
+			protected.GET("/user/fsrs", reviewHandler.GetFSRSParameters)
+			protected.POST("/user/fsrs/optimize", reviewHandler.OptimizeFSRS)
 			protected.GET("/auth/me", authHandler.GetCurrentUser)
 
 			// Vocabulary tracking
 			protected.POST("/words/:word", vocabularyHandler.AddWord)
 			protected.GET("/words", vocabularyHandler.GetUserWords)
+			protected.GET("/words/:word/notes", vocabularyHandler.GetNotes)
+			protected.PUT("/words/:word/notes", vocabularyHandler.UpdateNotes)
+			protected.GET("/words/:word/cards", vocabularyHandler.GetCards)
+			protected.PUT("/words/:word/cards", vocabularyHandler.SetCards)
 
 			// Spaced repetition reviews
 			protected.GET("/review", reviewHandler.GetDueWords)
+			protected.POST("/review/undo", reviewHandler.UndoReview)
 			protected.POST("/review/:word", reviewHandler.SubmitReview)
+			protected.POST("/review/:word/answer", reviewHandler.GradeReview)
 			protected.GET("/review/:word/history", reviewHandler.GetReviewHistory)
+
+			// Review sessions
+			protected.POST("/review/sessions", sessionHandler.CreateSession)
+			protected.GET("/review/sessions", sessionHandler.GetSessions)
+			protected.GET("/review/sessions/:id", sessionHandler.GetSession)
+			protected.GET("/review/sessions/:id/next", sessionHandler.NextCard)
+			protected.POST("/review/sessions/:id/answer", sessionHandler.Answer)
+			protected.POST("/review/sessions/:id/end", sessionHandler.EndSession)
+
+			// Multiple-choice quizzes
+			protected.GET("/quiz", quizHandler.GetQuiz)
+			protected.POST("/quiz/answer", quizHandler.AnswerQuiz)
+
+			// User-contributed dictionary changes
+			protected.POST("/words/:word/changes", moderationHandler.ProposeChange)
+			protected.GET("/user/changes", moderationHandler.GetUserChanges)
+
+			// Moderation queue (admins only)
+			admin := protected.Group("/admin")
+			admin.Use(auth.AdminMiddleware(adminToken))
+			{
+				admin.GET("/changes", moderationHandler.ListChanges)
+				admin.GET("/changes/:id", moderationHandler.GetChange)
+				admin.POST("/changes/:id/approve", moderationHandler.ApproveChange)
+				admin.POST("/changes/:id/reject", moderationHandler.RejectChange)
+			}
 		}
 	}
 
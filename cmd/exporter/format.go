@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/words-api/words/internal/models"
+	"github.com/words-api/words/internal/render"
+)
+
+// Writer streams words in one output format
+type Writer interface {
+	// Write adds a word to the output
+	Write(word *models.Word) error
+	// Close finishes the output, e.g. closing a JSON document, and flushes it
+	Close() error
+}
+
+// formats lists the output formats by name, each paired with the cmd/importer source that
+// reads it back
+var formats = map[string]struct {
+	newWriter func(io.Writer) Writer
+	source    string
+}{
+	"jsonl":         {newJSONLWriter, "jsonl"},
+	"wordset":       {newWordsetWriter, "wordset"},
+	"csv":           {newCSVWriter, "opted"},
+	"dictionaryapi": {newDictionaryAPIWriter, "dictionaryapi"},
+}
+
+// printFormats typeset the dictionary for printing, grouped by letter. They aren't meant to be
+// imported again.
+var printFormats = map[string]func(io.Writer, string) (*render.PrintDocument, error){
+	"latex": render.NewLaTeX,
+	"html":  render.NewPrintHTML,
+}
+
+// formatNames returns the format names in alphabetical order, for usage text
+func formatNames() []string {
+	names := make([]string, 0, len(formats)+len(printFormats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	for name := range printFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonlWriter writes one complete word per line, as the API returns it. It is lossless.
+type jsonlWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLWriter(out io.Writer) Writer {
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonlWriter{w: w, enc: enc}
+}
+
+func (j *jsonlWriter) Write(word *models.Word) error { return j.enc.Encode(word) }
+func (j *jsonlWriter) Close() error                  { return j.w.Flush() }
+
+// wordsetEntry is an entry in Wordset's data files
+type wordsetEntry struct {
+	Word         string           `json:"word"`
+	WordsetID    string           `json:"wordset_id,omitempty"`
+	Meanings     []wordsetMeaning `json:"meanings"`
+	Editors      []string         `json:"editors,omitempty"`
+	Contributors []string         `json:"contributors,omitempty"`
+}
+
+type wordsetMeaning struct {
+	ID         string   `json:"id,omitempty"`
+	Definition string   `json:"def"`
+	Example    string   `json:"example,omitempty"`
+	SpeechPart string   `json:"speech_part"`
+	Synonyms   []string `json:"synonyms,omitempty"`
+}
+
+// wordsetWriter writes one JSON object keyed by headword, like a Wordset data file. Wordset has
+// no phonetics, antonyms or part-of-speech-level synonyms, so those are left out.
+type wordsetWriter struct {
+	w     *bufio.Writer
+	first bool
+}
+
+func newWordsetWriter(out io.Writer) Writer {
+	return &wordsetWriter{w: bufio.NewWriter(out), first: true}
+}
+
+func (ws *wordsetWriter) Write(word *models.Word) error {
+	entry := wordsetEntry{Word: word.Word, WordsetID: word.WordsetID, Meanings: []wordsetMeaning{}}
+	for _, m := range word.Meanings {
+		for _, d := range m.Definitions {
+			entry.Meanings = append(entry.Meanings, wordsetMeaning{
+				ID:         d.ExternalID,
+				Definition: d.Definition,
+				Example:    d.Example,
+				SpeechPart: m.PartOfSpeech,
+				Synonyms:   d.Synonyms,
+			})
+		}
+	}
+	for _, c := range word.Credits {
+		switch c.Role {
+		case models.CreditEditor:
+			entry.Editors = append(entry.Editors, c.Name)
+		case models.CreditContributor:
+			entry.Contributors = append(entry.Contributors, c.Name)
+		}
+	}
+
+	key, err := marshal(word.Word, "")
+	if err != nil {
+		return err
+	}
+	value, err := marshal(entry, "  ")
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n  "
+	if ws.first {
+		sep, ws.first = "{\n  ", false
+	}
+	ws.w.WriteString(sep)
+	ws.w.Write(key)
+	ws.w.WriteString(": ")
+	_, err = ws.w.Write(value)
+	return err
+}
+
+func (ws *wordsetWriter) Close() error {
+	if ws.first {
+		ws.w.WriteString("{")
+	}
+	ws.w.WriteString("\n}\n")
+	return ws.w.Flush()
+}
+
+// csvWriter writes one row per definition with a Word, POS, Definition, Example, Synonyms header,
+// which cmd/importer's opted source reads by column name
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(out io.Writer) Writer {
+	w := csv.NewWriter(out)
+	w.Write([]string{"Word", "POS", "Definition", "Example", "Synonyms"})
+	return &csvWriter{w: w}
+}
+
+func (c *csvWriter) Write(word *models.Word) error {
+	for _, m := range word.Meanings {
+		for _, d := range m.Definitions {
+			if err := c.w.Write([]string{word.Word, m.PartOfSpeech, d.Definition, d.Example, strings.Join(d.Synonyms, "; ")}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// apiEntry is an entry in a dictionaryapi.dev response
+type apiEntry struct {
+	Word       string        `json:"word"`
+	Phonetic   string        `json:"phonetic,omitempty"`
+	Phonetics  []apiPhonetic `json:"phonetics"`
+	Meanings   []apiMeaning  `json:"meanings"`
+	SourceUrls []string      `json:"sourceUrls"`
+}
+
+type apiPhonetic struct {
+	Text  string `json:"text"`
+	Audio string `json:"audio"`
+}
+
+type apiMeaning struct {
+	PartOfSpeech string          `json:"partOfSpeech"`
+	Definitions  []apiDefinition `json:"definitions"`
+	Synonyms     []string        `json:"synonyms"`
+	Antonyms     []string        `json:"antonyms"`
+}
+
+type apiDefinition struct {
+	Definition string   `json:"definition"`
+	Example    string   `json:"example,omitempty"`
+	Synonyms   []string `json:"synonyms"`
+	Antonyms   []string `json:"antonyms"`
+}
+
+// dictionaryAPIWriter writes a JSON array of dictionaryapi.dev entries, one per line, so tools
+// built for that API can read the dictionary offline
+type dictionaryAPIWriter struct {
+	w     *bufio.Writer
+	first bool
+}
+
+func newDictionaryAPIWriter(out io.Writer) Writer {
+	return &dictionaryAPIWriter{w: bufio.NewWriter(out), first: true}
+}
+
+func (a *dictionaryAPIWriter) Write(word *models.Word) error {
+	entry := apiEntry{
+		Word:       word.Word,
+		Phonetic:   word.Phonetic,
+		Phonetics:  []apiPhonetic{},
+		Meanings:   []apiMeaning{},
+		SourceUrls: nonNil(word.SourceUrls),
+	}
+	for _, p := range word.Phonetics {
+		entry.Phonetics = append(entry.Phonetics, apiPhonetic{Text: p.Text, Audio: p.Audio})
+	}
+	for _, m := range word.Meanings {
+		meaning := apiMeaning{
+			PartOfSpeech: m.PartOfSpeech,
+			Definitions:  []apiDefinition{},
+			Synonyms:     nonNil(m.Synonyms),
+			Antonyms:     nonNil(m.Antonyms),
+		}
+		for _, d := range m.Definitions {
+			meaning.Definitions = append(meaning.Definitions, apiDefinition{
+				Definition: d.Definition,
+				Example:    d.Example,
+				Synonyms:   nonNil(d.Synonyms),
+				Antonyms:   nonNil(d.Antonyms),
+			})
+		}
+		entry.Meanings = append(entry.Meanings, meaning)
+	}
+
+	data, err := marshal(entry, "")
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n"
+	if a.first {
+		sep, a.first = "[\n", false
+	}
+	a.w.WriteString(sep)
+	_, err = a.w.Write(data)
+	return err
+}
+
+func (a *dictionaryAPIWriter) Close() error {
+	if a.first {
+		a.w.WriteString("[")
+	}
+	a.w.WriteString("\n]\n")
+	return a.w.Flush()
+}
+
+// marshal encodes v without escaping HTML characters, indented by indent if it isn't empty
+func marshal(v interface{}, indent string) ([]byte, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if indent != "" {
+		enc.SetIndent(indent, "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimSuffix(b.String(), "\n")), nil
+}
+
+// nonNil returns an empty slice for nil, as the API never sends null lists
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
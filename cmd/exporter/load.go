@@ -0,0 +1,299 @@
+package main
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// chunkSize is how many words are assembled per round of queries
+const chunkSize = 500
+
+// Filter selects the words to export; the zero value selects every word
+type Filter struct {
+	Words  []string
+	Prefix string
+	// Since keeps words added or changed at or after this time
+	Since time.Time
+	// Source keeps words with meanings or definitions from this dataset
+	Source string
+	// User keeps the words on this user's study list
+	User  string
+	Limit int
+}
+
+// Export orders
+const (
+	orderByWord = "word"
+	// orderByLetter puts headwords that don't start with a-z first, matching render.Letter's sections
+	orderByLetter = "CASE WHEN lower(substr(word, 1, 1)) BETWEEN 'a' AND 'z' THEN 1 ELSE 0 END, word"
+)
+
+// where builds the WHERE clause for the filter
+func (f Filter) where() (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if len(f.Words) > 0 {
+		clauses = append(clauses, "word IN ("+placeholders(len(f.Words))+")")
+		for _, w := range f.Words {
+			args = append(args, w)
+		}
+	}
+	if f.Prefix != "" {
+		clauses = append(clauses, `word LIKE ? ESCAPE '\'`)
+		args = append(args, likeEscaper.Replace(f.Prefix)+"%")
+	}
+	if !f.Since.IsZero() {
+		clauses = append(clauses, "updated_at >= ?")
+		args = append(args, f.Since)
+	}
+	if f.Source != "" {
+		clauses = append(clauses, `id IN (
+			SELECT word_id FROM meanings WHERE source = ?
+			UNION SELECT m.word_id FROM definitions d JOIN meanings m ON m.id = d.meaning_id WHERE d.source = ?
+		)`)
+		args = append(args, f.Source, f.Source)
+	}
+	if f.User != "" {
+		clauses = append(clauses, `id IN (
+			SELECT uw.word_id FROM user_words uw JOIN users u ON u.id = uw.user_id WHERE u.username = ?
+		)`)
+		args = append(args, f.User)
+	}
+
+	if len(clauses) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// eachWord streams the words matching the filter, sorted by orderBy, to fn. Words are
+// assembled a chunk at a time with one query per table, so memory stays flat however large
+// the dictionary is.
+func eachWord(db *sql.DB, filter Filter, orderBy string, fn func(*models.Word) error) (int, error) {
+	where, args := filter.where()
+	query := "SELECT id FROM words" + where + " ORDER BY " + orderBy
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := 0; i < len(ids); i += chunkSize {
+		words, err := loadWords(db, ids[i:min(i+chunkSize, len(ids))])
+		if err != nil {
+			return count, err
+		}
+		for _, w := range words {
+			if err := fn(w); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// loadWords assembles complete entries for ids, returned in the same order
+func loadWords(db *sql.DB, ids []int64) ([]*models.Word, error) {
+	in := placeholders(len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	byID := make(map[int64]*models.Word, len(ids))
+	meanings := make(map[int64][]*models.Meaning)
+	meaningByID := make(map[int64]*models.Meaning)
+	definitions := make(map[int64][]*models.Definition)
+	definitionByID := make(map[int64]*models.Definition)
+
+	err := scanRows(db, `
+		SELECT id, word, COALESCE(phonetic, ''), COALESCE(wordset_id, ''), created_at, updated_at
+		FROM words WHERE id IN (`+in+`)
+	`, args, func(rows *sql.Rows) error {
+		w := &models.Word{}
+		if err := rows.Scan(&w.ID, &w.Word, &w.Phonetic, &w.WordsetID, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return err
+		}
+		byID[w.ID] = w
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(db, `
+		SELECT id, word_id, text, COALESCE(audio, '') FROM phonetics WHERE word_id IN (`+in+`) ORDER BY id
+	`, args, func(rows *sql.Rows) error {
+		var p models.Phonetic
+		if err := rows.Scan(&p.ID, &p.WordID, &p.Text, &p.Audio); err != nil {
+			return err
+		}
+		byID[p.WordID].Phonetics = append(byID[p.WordID].Phonetics, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(db, `
+		SELECT id, word_id, part_of_speech FROM meanings WHERE word_id IN (`+in+`) ORDER BY id
+	`, args, func(rows *sql.Rows) error {
+		m := &models.Meaning{}
+		if err := rows.Scan(&m.ID, &m.WordID, &m.PartOfSpeech); err != nil {
+			return err
+		}
+		meanings[m.WordID] = append(meanings[m.WordID], m)
+		meaningByID[m.ID] = m
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(db, `
+		SELECT d.id, d.meaning_id, d.definition, COALESCE(d.example, ''), COALESCE(d.external_id, '')
+		FROM definitions d JOIN meanings m ON m.id = d.meaning_id
+		WHERE m.word_id IN (`+in+`) ORDER BY d.id
+	`, args, func(rows *sql.Rows) error {
+		d := &models.Definition{}
+		if err := rows.Scan(&d.ID, &d.MeaningID, &d.Definition, &d.Example, &d.ExternalID); err != nil {
+			return err
+		}
+		definitions[d.MeaningID] = append(definitions[d.MeaningID], d)
+		definitionByID[d.ID] = d
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range []string{"synonyms", "antonyms"} {
+		column := table[:len(table)-1]
+		err = scanRows(db, `
+			SELECT COALESCE(r.meaning_id, 0), COALESCE(r.definition_id, 0), r.`+column+`
+			FROM `+table+` r
+			WHERE r.meaning_id IN (SELECT id FROM meanings WHERE word_id IN (`+in+`))
+			OR r.definition_id IN (
+				SELECT d.id FROM definitions d JOIN meanings m ON m.id = d.meaning_id WHERE m.word_id IN (`+in+`)
+			)
+			ORDER BY r.id
+		`, append(args, args...), func(rows *sql.Rows) error {
+			var meaningID, definitionID int64
+			var value string
+			if err := rows.Scan(&meaningID, &definitionID, &value); err != nil {
+				return err
+			}
+			switch {
+			case definitionByID[definitionID] != nil:
+				d := definitionByID[definitionID]
+				if table == "synonyms" {
+					d.Synonyms = append(d.Synonyms, value)
+				} else {
+					d.Antonyms = append(d.Antonyms, value)
+				}
+			case meaningByID[meaningID] != nil:
+				m := meaningByID[meaningID]
+				if table == "synonyms" {
+					m.Synonyms = append(m.Synonyms, value)
+				} else {
+					m.Antonyms = append(m.Antonyms, value)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = scanRows(db, `
+		SELECT word_id, url FROM source_urls WHERE word_id IN (`+in+`) ORDER BY id
+	`, args, func(rows *sql.Rows) error {
+		var wordID int64
+		var url string
+		if err := rows.Scan(&wordID, &url); err != nil {
+			return err
+		}
+		byID[wordID].SourceUrls = append(byID[wordID].SourceUrls, url)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(db, `
+		SELECT word_id, name, role, source FROM word_credits WHERE word_id IN (`+in+`) ORDER BY role DESC, id
+	`, args, func(rows *sql.Rows) error {
+		var wordID int64
+		var c models.Credit
+		if err := rows.Scan(&wordID, &c.Name, &c.Role, &c.Source); err != nil {
+			return err
+		}
+		byID[wordID].Credits = append(byID[wordID].Credits, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	words := make([]*models.Word, 0, len(ids))
+	for _, id := range ids {
+		w, ok := byID[id]
+		if !ok {
+			// Deleted since the IDs were listed
+			continue
+		}
+		for _, m := range meanings[id] {
+			for _, d := range definitions[m.ID] {
+				m.Definitions = append(m.Definitions, *d)
+			}
+			w.Meanings = append(w.Meanings, *m)
+		}
+		words = append(words, w)
+	}
+	return words, nil
+}
+
+func scanRows(db *sql.DB, query string, args []interface{}, fn func(*sql.Rows) error) error {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/words-api/words/internal/models"
+)
+
+func main() {
+	dbPath := flag.String("db", "words.db", "Path to the SQLite database")
+	format := flag.String("format", "jsonl", "Output format: "+strings.Join(formatNames(), ", "))
+	outPath := flag.String("out", "-", "Output file (\"-\" for stdout)")
+	words := flag.String("words", "", "Comma-separated headwords to export")
+	prefix := flag.String("prefix", "", "Only export headwords starting with this prefix")
+	since := flag.String("since", "", "Only export words added or changed since this date (YYYY-MM-DD or RFC 3339)")
+	source := flag.String("source", "", "Only export words with meanings or definitions from this import source (e.g. wordset)")
+	user := flag.String("user", "", "Only export the words on this user's study list")
+	limit := flag.Int("limit", 0, "Export at most this many words (0 for all)")
+	title := flag.String("title", "", "Title page for the latex and html print formats (default \"Dictionary\", or the user's study list)")
+	flag.Usage = func() {
+		fmt.Println("Usage: exporter [-format name] [-out path] [filters]")
+		fmt.Println("Example: exporter -out backup.jsonl")
+		fmt.Println("         exporter -format wordset -prefix a -out a.json")
+		fmt.Println("         exporter -format latex -user alice -out alice.tex")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	f, ok := formats[*format]
+	newPrint, isPrint := printFormats[*format]
+	if !ok && !isPrint {
+		log.Fatalf("Unknown format %q (available: %s)", *format, strings.Join(formatNames(), ", "))
+	}
+
+	filter := Filter{Prefix: strings.ToLower(*prefix), Source: *source, User: *user, Limit: *limit}
+	for _, w := range strings.Split(*words, ",") {
+		if w = strings.TrimSpace(strings.ToLower(w)); w != "" {
+			filter.Words = append(filter.Words, w)
+		}
+	}
+	if *since != "" {
+		t, err := parseSince(*since)
+		if err != nil {
+			log.Fatalf("Invalid -since %q: %v", *since, err)
+		}
+		filter.Since = t
+	}
+
+	db, err := openReadOnly(*dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if *user != "" {
+		var exists bool
+		if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE username = ?)", *user).Scan(&exists); err != nil {
+			log.Fatalf("Failed to look up user: %v", err)
+		}
+		if !exists {
+			log.Fatalf("User %q not found", *user)
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "-" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *outPath, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	startTime := time.Now()
+	var w Writer
+	orderBy := orderByWord
+	if isPrint {
+		if *title == "" {
+			*title = "Dictionary"
+			if *user != "" {
+				*title = *user + "'s Study List"
+			}
+		}
+		if w, err = newPrint(out, *title); err != nil {
+			log.Fatalf("Failed to write output: %v", err)
+		}
+		// Sections follow render.Letter, which puts symbols and numbers first
+		orderBy = orderByLetter
+	} else {
+		w = f.newWriter(out)
+	}
+
+	count, err := eachWord(db, filter, orderBy, func(word *models.Word) error {
+		return w.Write(word)
+	})
+	if err != nil {
+		log.Fatalf("Export failed after %d words: %v", count, err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatalf("Failed to write output: %v", err)
+	}
+
+	// Progress goes to stderr so stdout holds only the export
+	fmt.Fprintf(os.Stderr, "✅ Exported %d words as %s in %s", count, *format, time.Since(startTime).Round(time.Millisecond))
+	if !isPrint {
+		fmt.Fprintf(os.Stderr, " (import with: importer -source %s)", f.source)
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+// parseSince accepts a date or a full timestamp
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// openReadOnly opens an existing database without creating or migrating it, so an export
+// never changes the schema. It must already be up to date; the API migrates it on startup.
+func openReadOnly(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
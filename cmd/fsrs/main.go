@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/words-api/words/internal/database"
+	"github.com/words-api/words/internal/services"
+)
+
+func main() {
+	dbPath := flag.String("db", "words.db", "Path to the SQLite database")
+	save := flag.Bool("save", false, "Schedule the user's FSRS reviews with the fitted weights")
+	asJSON := flag.Bool("json", false, "Print the result as JSON")
+	flag.Usage = func() {
+		fmt.Println("Usage: fsrs [-save] [-json] <username>...")
+		fmt.Println("Fits FSRS weights to each user's review history and reports how well they predict it.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	db, err := database.InitDB(*dbPath)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	service := services.NewReviewService(db)
+	failed := false
+	for _, username := range flag.Args() {
+		startTime := time.Now()
+		result, err := service.OptimizeFSRS(username, *save)
+		if err != nil {
+			log.Printf("%s: %v", username, err)
+			failed = true
+			continue
+		}
+
+		if *asJSON {
+			data, _ := json.MarshalIndent(map[string]interface{}{"username": username, "result": result}, "", "  ")
+			fmt.Println(string(data))
+			continue
+		}
+
+		weights := make([]string, len(result.Weights))
+		for i, w := range result.Weights {
+			weights[i] = fmt.Sprintf("%.4f", w)
+		}
+		fmt.Printf("🧠 %s: fitted to %d reviews of %d words in %s\n", username, result.Reviews, result.Words, time.Since(startTime).Round(time.Millisecond))
+		fmt.Printf("   Weights: %s\n", strings.Join(weights, ", "))
+		fmt.Printf("   Log loss: %.4f → %.4f\n", result.LogLossBefore, result.LogLoss)
+		fmt.Printf("   Retention: %.1f%% actual, %.1f%% expected (scheduling for %.0f%%)\n",
+			result.ActualRetention*100, result.ExpectedRetention*100, result.DesiredRetention*100)
+		if result.Saved {
+			fmt.Println("   ✅ Saved")
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
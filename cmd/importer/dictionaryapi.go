@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/words-api/words/internal/models"
+	"github.com/words-api/words/pkg/dictionary"
+)
+
+// dictionaryAPISource reads JSON arrays of dictionaryapi.dev entries: saved API responses, or
+// cmd/exporter's dictionaryapi format
+type dictionaryAPISource struct{}
+
+func (dictionaryAPISource) Name() string { return "dictionaryapi" }
+
+func (dictionaryAPISource) Files(path string) ([]string, error) {
+	return globFiles(path, "*.json")
+}
+
+func (dictionaryAPISource) Load(file string, index *WordIndex) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	lines := newLineCounter(f)
+	dec := json.NewDecoder(lines)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return 0, fmt.Errorf("expected a JSON array of entries")
+	}
+
+	count := 0
+	for dec.More() {
+		loc := Location{File: file, Line: lines.at(dec.InputOffset())}
+
+		// One-element response, so each entry decodes into the API's own struct
+		entry := make(models.DictionaryAPIResponse, 1)
+		if err := dec.Decode(&entry[0]); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return count, fmt.Errorf("line %d: %w", loc.Line, err)
+			}
+			index.Problem(loc, issueParseError, "%v", err)
+			continue
+		}
+
+		word := dictionary.ConvertAPIResponse(entry[0])
+		word.Word = strings.ToLower(word.Word)
+		for i := range word.Meanings {
+			word.Meanings[i].PartOfSpeech = normalizePartOfSpeech(word.Meanings[i].PartOfSpeech)
+		}
+
+		loc.Key = word.Word
+		index.Add(loc, word)
+		count++
+	}
+
+	return count, nil
+}
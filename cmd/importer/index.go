@@ -0,0 +1,207 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// WordIndex tracks words for deduplication
+type WordIndex struct {
+	words map[string]*models.Word
+
+	// With a spiller, the words are written out as a sorted run whenever limit are held, so
+	// a corpus larger than memory can still be deduplicated (see spill.go)
+	spill    *spiller
+	limit    int
+	runs     []string
+	spillErr error
+
+	// What validation and deduplication found, for the import report
+	issues               []Issue
+	duplicates           int
+	duplicateDefinitions int
+}
+
+func NewWordIndex() *WordIndex {
+	return &WordIndex{
+		words: make(map[string]*models.Word),
+	}
+}
+
+// Add validates an entry read from a source at loc and adds or merges it. Problems are
+// recorded as issues; entries left without any definitions are skipped.
+func (idx *WordIndex) Add(loc Location, word *models.Word) {
+	issues := validateWord(loc, word)
+	idx.issues = append(idx.issues, issues...)
+	if len(word.Meanings) == 0 {
+		return
+	}
+	idx.AddOrMerge(word)
+}
+
+// Problem records an issue with an entry that couldn't be read
+func (idx *WordIndex) Problem(loc Location, kind, format string, args ...interface{}) {
+	idx.issues = append(idx.issues, newIssue(loc, kind, format, args...))
+}
+
+// AddOrMerge adds a word or merges meanings if it already exists
+func (idx *WordIndex) AddOrMerge(word *models.Word) {
+	key := wordKey(word)
+
+	if existing, exists := idx.words[key]; exists {
+		idx.duplicates++
+		idx.duplicateDefinitions += foldWord(existing, word)
+		return
+	}
+
+	idx.words[key] = word
+	if idx.spill != nil && len(idx.words) >= idx.limit {
+		idx.flush()
+	}
+}
+
+// flush writes the words held to a spill run and starts afresh. A failure is kept for the
+// loader to report, since Add has no way to return it.
+func (idx *WordIndex) flush() {
+	if idx.spillErr == nil {
+		path, err := idx.spill.write(idx.GetAll())
+		idx.runs = append(idx.runs, path)
+		idx.spillErr = err
+	}
+	idx.words = make(map[string]*models.Word)
+}
+
+// wordKey is what words are deduplicated and sorted by
+func wordKey(word *models.Word) string {
+	return strings.ToLower(word.Word)
+}
+
+// foldWord merges a duplicate entry into existing and returns the number of definitions it
+// already had
+func foldWord(existing, word *models.Word) int {
+	skipped := 0
+	// Merge meanings - fold definitions into the meaning for the same part of speech
+	for _, m := range word.Meanings {
+		skipped += mergeMeaning(existing, m)
+	}
+	// Update phonetic if the new one has more info
+	if word.Phonetic != "" && len(word.Phonetic) > len(existing.Phonetic) {
+		existing.Phonetic = word.Phonetic
+	}
+	// Merge source URLs
+	existing.SourceUrls = appendUnique(existing.SourceUrls, word.SourceUrls...)
+	// Merge phonetics
+	for _, p := range word.Phonetics {
+		if !hasPhonetic(existing.Phonetics, p.Text) {
+			existing.Phonetics = append(existing.Phonetics, p)
+		}
+	}
+	// Keep the first upstream ID and every credit
+	if existing.WordsetID == "" {
+		existing.WordsetID = word.WordsetID
+	}
+	for _, c := range word.Credits {
+		if !hasCredit(existing.Credits, c) {
+			existing.Credits = append(existing.Credits, c)
+		}
+	}
+	return skipped
+}
+
+// Merge folds another index's words and counts into this one. Issues stay with the other
+// index, which reports them per file.
+func (idx *WordIndex) Merge(other *WordIndex) {
+	for _, word := range other.words {
+		idx.AddOrMerge(word)
+	}
+	idx.duplicates += other.duplicates
+	idx.duplicateDefinitions += other.duplicateDefinitions
+}
+
+// GetAll returns the words in key order, which keeps inserts into the words index local and
+// lets an interrupted import resume after the last word it wrote
+func (idx *WordIndex) GetAll() []*models.Word {
+	result := make([]*models.Word, 0, len(idx.words))
+	for _, word := range idx.words {
+		result = append(result, word)
+	}
+	sort.Slice(result, func(i, j int) bool { return wordKey(result[i]) < wordKey(result[j]) })
+	return result
+}
+
+// mergeMeaning adds a meaning's definitions to the word's meaning with the same part of speech,
+// skipping definitions that are already present. It returns the number skipped.
+func mergeMeaning(word *models.Word, m models.Meaning) int {
+	for i := range word.Meanings {
+		existing := &word.Meanings[i]
+		if existing.PartOfSpeech != m.PartOfSpeech {
+			continue
+		}
+		skipped := 0
+		for _, d := range m.Definitions {
+			if hasDefinition(existing.Definitions, d.Definition) {
+				skipped++
+				continue
+			}
+			existing.Definitions = append(existing.Definitions, d)
+		}
+		existing.Synonyms = appendUnique(existing.Synonyms, m.Synonyms...)
+		existing.Antonyms = appendUnique(existing.Antonyms, m.Antonyms...)
+		return skipped
+	}
+	word.Meanings = append(word.Meanings, m)
+	return 0
+}
+
+// normalizeText folds case and whitespace so near-identical text compares equal
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+func hasDefinition(defs []models.Definition, text string) bool {
+	key := normalizeText(text)
+	for _, d := range defs {
+		if normalizeText(d.Definition) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPhonetic(phonetics []models.Phonetic, text string) bool {
+	key := normalizeText(text)
+	for _, p := range phonetics {
+		if normalizeText(p.Text) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCredit(credits []models.Credit, c models.Credit) bool {
+	for _, existing := range credits {
+		if existing == c {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUnique appends values not already present (compared after normalization)
+func appendUnique(list []string, values ...string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		seen[normalizeText(v)] = true
+	}
+	for _, v := range values {
+		key := normalizeText(v)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		list = append(list, v)
+	}
+	return list
+}
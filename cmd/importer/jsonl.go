@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// jsonlSource reads cmd/exporter's JSONL format: one complete word, as the API returns it, per
+// line. It keeps everything the database holds, so it is the format to restore backups from.
+type jsonlSource struct{}
+
+func (jsonlSource) Name() string { return "jsonl" }
+
+func (jsonlSource) Files(path string) ([]string, error) {
+	return globFiles(path, "*.jsonl")
+}
+
+func (jsonlSource) Load(file string, index *WordIndex) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+
+	count := 0
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		loc := Location{File: file, Line: lineNum}
+		var word models.Word
+		if err := json.Unmarshal(line, &word); err != nil {
+			index.Problem(loc, issueParseError, "%v", err)
+			continue
+		}
+
+		loc.Key = word.Word
+		index.Add(loc, &word)
+		count++
+	}
+
+	return count, scanner.Err()
+}
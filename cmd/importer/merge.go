@@ -0,0 +1,337 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// mergeWord adds the parts of word that the stored entry doesn't have yet. Meanings are matched
+// by part of speech, definitions by upstream ID or else normalized text, and synonyms, antonyms
+// and phonetics by normalized text, so re-importing the same data is a no-op. New rows are
+// tagged with source.
+func (b *importBatch) mergeWord(wordID int64, word *models.Word, source string) error {
+	stored, err := b.loadMeanings(wordID)
+	if err != nil {
+		return err
+	}
+
+	added := 0
+	for _, m := range word.Meanings {
+		pos := normalizeText(m.PartOfSpeech)
+		meaningID, ok := stored.meanings[pos]
+		if !ok {
+			if err := b.insertMeaning(wordID, m, source); err != nil {
+				return err
+			}
+			added++
+			continue
+		}
+
+		for _, rel := range []struct {
+			table  string
+			values []string
+		}{{"synonyms", m.Synonyms}, {"antonyms", m.Antonyms}} {
+			n, err := b.insertMissing(rel.table, "meaning_id", meaningID, rel.values, source)
+			if err != nil {
+				return err
+			}
+			added += n
+		}
+
+		for _, d := range m.Definitions {
+			definitionID, ok := stored.externalIDs[d.ExternalID]
+			if !ok {
+				definitionID, ok = stored.definitions[pos][normalizeText(d.Definition)]
+			}
+			if !ok {
+				if err := b.insertDefinition(meaningID, d, source); err != nil {
+					return err
+				}
+				added++
+				continue
+			}
+
+			for _, rel := range []struct {
+				table  string
+				values []string
+			}{{"synonyms", d.Synonyms}, {"antonyms", d.Antonyms}} {
+				n, err := b.insertMissing(rel.table, "definition_id", definitionID, rel.values, source)
+				if err != nil {
+					return err
+				}
+				added += n
+			}
+		}
+	}
+
+	phonetics, err := b.loadStrings("SELECT text FROM phonetics WHERE word_id = ?", wordID)
+	if err != nil {
+		return err
+	}
+	for _, p := range word.Phonetics {
+		if len(appendUnique(phonetics, p.Text)) == len(phonetics) {
+			continue
+		}
+		if err := b.insertPhonetic(wordID, p, source); err != nil {
+			return err
+		}
+		phonetics = append(phonetics, p.Text)
+		added++
+	}
+
+	urls, err := b.loadStrings("SELECT url FROM source_urls WHERE word_id = ?", wordID)
+	if err != nil {
+		return err
+	}
+	for _, url := range appendUnique(urls, word.SourceUrls...)[len(urls):] {
+		if err := b.insertSourceURL(wordID, url, source); err != nil {
+			return err
+		}
+		added++
+	}
+
+	credits, err := b.insertCredits(wordID, word.Credits)
+	if err != nil {
+		return err
+	}
+	added += int(credits)
+
+	if word.WordsetID != "" {
+		n, err := b.execCount(`UPDATE words SET wordset_id = ? WHERE id = ? AND wordset_id IS NULL`, word.WordsetID, wordID)
+		if err != nil {
+			return err
+		}
+		added += int(n)
+	}
+
+	if added == 0 {
+		return nil
+	}
+	return b.touchWord(wordID, word.Phonetic)
+}
+
+// removeSourceRows clears what source contributed to a word before it is merged again. Its
+// definitions that are still in word, matched by upstream ID or else by text, are kept and
+// refreshed in place so their IDs stay stable for notes and moderation; the rest are deleted
+// along with their synonyms and antonyms, as are the source's synonyms, antonyms, phonetics,
+// source URLs and credits. Foreign keys aren't enforced, so dependents are removed (or
+// unlinked) explicitly.
+func (b *importBatch) removeSourceRows(wordID int64, word *models.Word, source string) error {
+	byText := make(map[string]models.Definition)
+	byExternalID := make(map[string]models.Definition)
+	for _, m := range word.Meanings {
+		for _, d := range m.Definitions {
+			byText[normalizeText(m.PartOfSpeech)+"\x00"+normalizeText(d.Definition)] = d
+			if d.ExternalID != "" {
+				byExternalID[d.ExternalID] = d
+			}
+		}
+	}
+
+	rows, err := b.query(`
+		SELECT d.id, m.part_of_speech, d.definition, COALESCE(d.example, ''), COALESCE(d.external_id, '')
+		FROM definitions d
+		JOIN meanings m ON m.id = d.meaning_id
+		WHERE m.word_id = ? AND d.source = ?
+	`, wordID, source)
+	if err != nil {
+		return err
+	}
+
+	var stale []int64
+	refreshed := make(map[int64]models.Definition)
+	for rows.Next() {
+		var id int64
+		var pos, definition, example, externalID string
+		if err := rows.Scan(&id, &pos, &definition, &example, &externalID); err != nil {
+			rows.Close()
+			return err
+		}
+		d, ok := byExternalID[externalID]
+		if !ok {
+			d, ok = byText[normalizeText(pos)+"\x00"+normalizeText(definition)]
+		}
+		switch {
+		case !ok:
+			stale = append(stale, id)
+		case d.Definition != definition || d.Example != example || d.ExternalID != externalID:
+			refreshed[id] = d
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	changed := int64(0)
+	for id, d := range refreshed {
+		if _, err := b.exec(`
+			UPDATE definitions SET definition = ?, example = ?, external_id = ? WHERE id = ?
+		`, d.Definition, d.Example, nullString(d.ExternalID), id); err != nil {
+			return err
+		}
+		changed++
+	}
+
+	for _, id := range stale {
+		for _, query := range []string{
+			`DELETE FROM synonyms WHERE definition_id = ?`,
+			`DELETE FROM antonyms WHERE definition_id = ?`,
+			// Rows elsewhere that point at the definition (ON DELETE SET NULL)
+			`UPDATE user_word_notes SET preferred_definition_id = NULL WHERE preferred_definition_id = ?`,
+			`UPDATE pending_changes SET definition_id = NULL WHERE definition_id = ?`,
+			`DELETE FROM definitions WHERE id = ?`,
+		} {
+			if _, err := b.exec(query, id); err != nil {
+				return err
+			}
+		}
+		changed++
+	}
+
+	// Meanings from this source that no longer have definitions, with any remaining relations
+	const emptyMeanings = `SELECT id FROM meanings m WHERE m.word_id = ? AND m.source = ?
+		AND NOT EXISTS (SELECT 1 FROM definitions d WHERE d.meaning_id = m.id)`
+	for _, table := range []string{"synonyms", "antonyms"} {
+		if _, err := b.exec(`DELETE FROM `+table+` WHERE meaning_id IN (`+emptyMeanings+`)`, wordID, source); err != nil {
+			return err
+		}
+	}
+	n, err := b.execCount(`DELETE FROM meanings WHERE id IN (`+emptyMeanings+`)`, wordID, source)
+	if err != nil {
+		return err
+	}
+	changed += n
+
+	// The remaining contributions carry no IDs anyone refers to, so they are simply re-merged
+	const wordMeanings = `SELECT id FROM meanings WHERE word_id = ?`
+	const wordDefinitions = `SELECT d.id FROM definitions d JOIN meanings m ON m.id = d.meaning_id WHERE m.word_id = ?`
+	for _, table := range []string{"synonyms", "antonyms"} {
+		query := `DELETE FROM ` + table + ` WHERE source = ? AND (meaning_id IN (` + wordMeanings + `) OR definition_id IN (` + wordDefinitions + `))`
+		if _, err := b.exec(query, source, wordID, wordID); err != nil {
+			return err
+		}
+	}
+	for _, table := range []string{"phonetics", "source_urls", "word_credits"} {
+		if _, err := b.exec(`DELETE FROM `+table+` WHERE word_id = ? AND source = ?`, wordID, source); err != nil {
+			return err
+		}
+	}
+
+	if changed == 0 {
+		return nil
+	}
+	return b.touchWord(wordID, "")
+}
+
+// storedMeanings indexes a word's stored meanings and definitions for matching
+type storedMeanings struct {
+	// meanings maps a normalized part of speech to the first meaning with it
+	meanings map[string]int64
+	// definitions maps part of speech and normalized text to a definition
+	definitions map[string]map[string]int64
+	// externalIDs maps upstream sense IDs to definitions
+	externalIDs map[string]int64
+}
+
+func (b *importBatch) loadMeanings(wordID int64) (*storedMeanings, error) {
+	rows, err := b.query(`
+		SELECT m.id, m.part_of_speech, d.id, d.definition, d.external_id
+		FROM meanings m
+		LEFT JOIN definitions d ON d.meaning_id = m.id
+		WHERE m.word_id = ?
+		ORDER BY m.id, d.id
+	`, wordID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stored := &storedMeanings{
+		meanings:    make(map[string]int64),
+		definitions: make(map[string]map[string]int64),
+		externalIDs: make(map[string]int64),
+	}
+	for rows.Next() {
+		var meaningID int64
+		var pos string
+		var definitionID sql.NullInt64
+		var definition, externalID sql.NullString
+		if err := rows.Scan(&meaningID, &pos, &definitionID, &definition, &externalID); err != nil {
+			return nil, err
+		}
+
+		pos = normalizeText(pos)
+		if _, ok := stored.meanings[pos]; !ok {
+			stored.meanings[pos] = meaningID
+			stored.definitions[pos] = make(map[string]int64)
+		}
+		if !definitionID.Valid {
+			continue
+		}
+		key := normalizeText(definition.String)
+		if _, ok := stored.definitions[pos][key]; !ok {
+			stored.definitions[pos][key] = definitionID.Int64
+		}
+		if externalID.String != "" {
+			stored.externalIDs[externalID.String] = definitionID.Int64
+		}
+	}
+
+	return stored, rows.Err()
+}
+
+// insertMissing inserts the synonyms or antonyms the owner doesn't already have
+func (b *importBatch) insertMissing(table, ownerColumn string, ownerID int64, values []string, source string) (int, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", table[:len(table)-1], table, ownerColumn)
+	existing, err := b.loadStrings(query, ownerID)
+	if err != nil {
+		return 0, err
+	}
+
+	missing := appendUnique(existing, values...)[len(existing):]
+	return len(missing), b.insertRelated(table, ownerColumn, ownerID, missing, source)
+}
+
+func (b *importBatch) loadStrings(query string, args ...interface{}) ([]string, error) {
+	rows, err := b.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v sql.NullString
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v.String)
+	}
+	return values, rows.Err()
+}
+
+func (b *importBatch) execCount(query string, args ...interface{}) (int64, error) {
+	result, err := b.exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// touchWord bumps updated_at so cached lookups revalidate, and fills in a missing phonetic
+func (b *importBatch) touchWord(wordID int64, phonetic string) error {
+	_, err := b.exec(`
+		UPDATE words
+		SET updated_at = ?, phonetic = CASE WHEN phonetic IS NULL OR phonetic = '' THEN ? ELSE phonetic END
+		WHERE id = ?
+	`, time.Now(), phonetic, wordID)
+	return err
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// storedDefinition is a definition row of a word
+type storedDefinition struct {
+	id                    int64
+	text, example, source string
+	synonyms              string
+}
+
+// storedDefinitions returns a word's definitions in insertion order, each with its synonyms
+func storedDefinitions(t *testing.T, db *sql.DB, word string) []storedDefinition {
+	t.Helper()
+	rows, err := db.Query(`
+		SELECT d.id, d.definition, COALESCE(d.example, ''), d.source,
+		       COALESCE((SELECT GROUP_CONCAT(synonym, ',') FROM (SELECT synonym FROM synonyms WHERE definition_id = d.id ORDER BY id)), '')
+		FROM definitions d
+		JOIN meanings m ON m.id = d.meaning_id
+		JOIN words w ON w.id = m.word_id
+		WHERE w.word = ?
+		ORDER BY d.id
+	`, word)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var definitions []storedDefinition
+	for rows.Next() {
+		var d storedDefinition
+		if err := rows.Scan(&d.id, &d.text, &d.example, &d.source, &d.synonyms); err != nil {
+			t.Fatal(err)
+		}
+		definitions = append(definitions, d)
+	}
+	return definitions
+}
+
+// meaningSynonyms returns the synonyms of a word's meanings in insertion order
+func meaningSynonyms(t *testing.T, db *sql.DB, word string) []string {
+	t.Helper()
+	rows, err := db.Query(`
+		SELECT s.synonym FROM synonyms s
+		JOIN meanings m ON m.id = s.meaning_id
+		JOIN words w ON w.id = m.word_id
+		WHERE w.word = ?
+		ORDER BY s.id
+	`, word)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var synonyms []string
+	for rows.Next() {
+		var synonym string
+		if err := rows.Scan(&synonym); err != nil {
+			t.Fatal(err)
+		}
+		synonyms = append(synonyms, synonym)
+	}
+	return synonyms
+}
+
+func TestMergeKeepsExistingRows(t *testing.T) {
+	db := openTestDB(t)
+	importWords(t, db, modeSkip, "wordset", testWord("quick", "adjective", []string{"fast"},
+		models.Definition{Definition: "moving fast", Synonyms: []string{"speedy"}},
+	))
+	before := storedDefinitions(t, db, "quick")
+
+	merged := testWord("quick", "adjective", []string{"Fast", "swift"},
+		models.Definition{Definition: "Moving  fast", Synonyms: []string{"speedy", "rapid"}},
+		models.Definition{Definition: "quick to understand", Synonyms: []string{"bright"}},
+	)
+	importWords(t, db, modeMerge, "opted", merged)
+
+	want := []storedDefinition{
+		{id: before[0].id, text: "moving fast", source: "wordset", synonyms: "speedy,rapid"},
+		{text: "quick to understand", source: "opted", synonyms: "bright"},
+	}
+	got := storedDefinitions(t, db, "quick")
+	if len(got) == 2 {
+		want[1].id = got[1].id
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("definitions after merge:\n%+v\nwant:\n%+v", got, want)
+	}
+	if synonyms := meaningSynonyms(t, db, "quick"); !reflect.DeepEqual(synonyms, []string{"fast", "swift"}) {
+		t.Errorf("meaning synonyms %v, want [fast swift]", synonyms)
+	}
+
+	// Merging the same entry again adds nothing
+	counts := map[string]int{}
+	for _, table := range []string{"meanings", "definitions", "synonyms", "source_urls"} {
+		counts[table] = countRows(t, db, table)
+	}
+	importWords(t, db, modeMerge, "opted", merged)
+	for table, n := range counts {
+		if after := countRows(t, db, table); after != n {
+			t.Errorf("merging again left %d %s, want %d", after, table, n)
+		}
+	}
+}
+
+func TestUpdateReplacesOnlyItsSourceRows(t *testing.T) {
+	db := openTestDB(t)
+	importWords(t, db, modeSkip, "wordset", testWord("bank", "noun", nil,
+		models.Definition{Definition: "the land alongside a river", Synonyms: []string{"shore"}},
+	))
+	importWords(t, db, modeMerge, "opted", testWord("bank", "noun", []string{"depository"},
+		models.Definition{Definition: "an institution that keeps money", Example: "the bank opens at nine"},
+		models.Definition{Definition: "a long ridge of earth", Synonyms: []string{"mound"}},
+	))
+	before := storedDefinitions(t, db, "bank")
+
+	importWords(t, db, modeUpdate, "opted", testWord("bank", "noun", []string{"lender"},
+		models.Definition{Definition: "an institution that keeps money", Example: "she went to the bank"},
+		models.Definition{Definition: "a row of similar objects", Synonyms: []string{"array"}},
+	))
+
+	got := storedDefinitions(t, db, "bank")
+	want := []storedDefinition{
+		// The other source's definition is untouched
+		before[0],
+		// Still present, so refreshed in place and keeping its ID
+		{id: before[1].id, text: "an institution that keeps money", example: "she went to the bank", source: "opted"},
+		{text: "a row of similar objects", source: "opted", synonyms: "array"},
+	}
+	if len(got) == 3 {
+		want[2].id = got[2].id
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("definitions after update:\n%+v\nwant:\n%+v", got, want)
+	}
+	if synonyms := meaningSynonyms(t, db, "bank"); !reflect.DeepEqual(synonyms, []string{"lender"}) {
+		t.Errorf("meaning synonyms %v, want [lender]", synonyms)
+	}
+	var orphaned int
+	if err := db.QueryRow("SELECT COUNT(*) FROM synonyms WHERE synonym = 'mound'").Scan(&orphaned); err != nil {
+		t.Fatal(err)
+	}
+	if orphaned != 0 {
+		t.Errorf("the removed definition's synonym is still stored")
+	}
+}
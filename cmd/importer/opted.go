@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// optedSource reads the OPTED (Online Plain Text English Dictionary, Webster's 1913) CSV export.
+// Columns are found by header name (Word, POS, Definition, plus optional Example and Synonyms);
+// files without a header are read as Word, POS, Definition.
+type optedSource struct{}
+
+func (optedSource) Name() string { return "opted" }
+
+func (optedSource) Files(path string) ([]string, error) {
+	return globFiles(path, "*.csv")
+}
+
+func (optedSource) Load(file string, index *WordIndex) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	columns := map[string]int{"word": 0, "pos": 1, "definition": 2, "example": -1, "synonyms": -1}
+	count := 0
+	first := true
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return count, err
+			}
+			index.Problem(Location{File: file, Line: parseErr.Line}, issueParseError, "%v", parseErr.Err)
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		if first {
+			first = false
+			if header := optedHeader(record); header != nil {
+				columns = header
+				continue
+			}
+		}
+
+		word := strings.TrimSpace(field(record, columns["word"]))
+		definition := strings.TrimSpace(field(record, columns["definition"]))
+
+		def := models.Definition{
+			Definition: definition,
+			Example:    strings.TrimSpace(field(record, columns["example"])),
+		}
+		if synonyms := field(record, columns["synonyms"]); synonyms != "" {
+			for _, syn := range strings.Split(synonyms, ";") {
+				if syn = strings.TrimSpace(syn); syn != "" {
+					def.Synonyms = append(def.Synonyms, syn)
+				}
+			}
+		}
+
+		index.Add(Location{File: file, Line: line, Key: word}, &models.Word{
+			Word:       strings.ToLower(word),
+			SourceUrls: []string{"http://www.mso.anu.edu.au/~ralph/OPTED/"},
+			Meanings: []models.Meaning{{
+				PartOfSpeech: normalizePartOfSpeech(strings.Trim(field(record, columns["pos"]), "() ")),
+				Definitions:  []models.Definition{def},
+			}},
+		})
+		count++
+	}
+
+	return count, nil
+}
+
+// optedHeader maps column names to indexes if the record is a header row
+func optedHeader(record []string) map[string]int {
+	columns := map[string]int{"word": -1, "pos": -1, "definition": -1, "example": -1, "synonyms": -1}
+	for i, name := range record {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "word":
+			columns["word"] = i
+		case "pos", "part_of_speech", "partofspeech":
+			columns["pos"] = i
+		case "definition", "def":
+			columns["definition"] = i
+		case "example":
+			columns["example"] = i
+		case "synonyms":
+			columns["synonyms"] = i
+		}
+	}
+	if columns["word"] < 0 {
+		return nil
+	}
+	if columns["definition"] < 0 {
+		columns["definition"] = len(record) - 1
+	}
+	return columns
+}
+
+// field returns the value at index i, or "" if the column is absent
+func field(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestOptedSource(t *testing.T) {
+	runSourceTests(t, optedSource{}, "opted.csv", []sourceTest{
+		{
+			name: "header row",
+			content: `word,pos,def
+"Abandon","v. t.","To give up absolutely; to forsake entirely."
+"Abacus","n.","A table or tray strewn with sand, anciently used for drawing, calculating, etc."
+`,
+			words: []string{
+				"abacus|noun|A table or tray strewn with sand, anciently used for drawing, calculating, etc.||",
+				"abandon|verb|To give up absolutely; to forsake entirely.||",
+			},
+			count: 2,
+		},
+		{
+			name:    "no header",
+			content: "Abandon,v. t.,To give up absolutely\n",
+			words:   []string{"abandon|verb|To give up absolutely||"},
+			count:   1,
+		},
+		{
+			name: "example and synonyms columns",
+			content: `Word,POS,Definition,Example,Synonyms
+Abase,(v. t.),To lower or depress,to abase the eye,lower; humble;
+`,
+			words: []string{"abase|verb|To lower or depress|to abase the eye|lower,humble"},
+			count: 1,
+		},
+		{
+			name: "malformed rows",
+			content: `word,pos,def
+Abandon
+,n.,A headword is missing
+Abb,zz.,"A yarn, but the part of speech is unknown"
+`,
+			words:  []string{"abb|zz.|A yarn, but the part of speech is unknown||"},
+			issues: []string{issueUnknownPartOfSpeech, issueEmptyDefinition, issueNoDefinitions, issueEmptyWord, issueUnknownPartOfSpeech},
+			count:  3,
+		},
+	})
+}
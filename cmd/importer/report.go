@@ -0,0 +1,153 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Report summarizes what an import found in its source files and what it would change
+type Report struct {
+	Source               string         `json:"source"`
+	Path                 string         `json:"path"`
+	DryRun               bool           `json:"dry_run"`
+	Entries              int            `json:"entries"`
+	UniqueWords          int            `json:"unique_words"`
+	DuplicatesMerged     int            `json:"duplicates_merged"`
+	DuplicateDefinitions int            `json:"duplicate_definitions"`
+	EmptyDefinitions     int            `json:"empty_definitions"`
+	MalformedDefinitions int            `json:"malformed_definitions"`
+	ParseErrors          int            `json:"parse_errors"`
+	UnknownPartsOfSpeech map[string]int `json:"unknown_parts_of_speech"`
+	NewWords             int            `json:"new_words"`
+	ExistingWords        int            `json:"existing_words"`
+	Files                []FileReport   `json:"files"`
+}
+
+// FileReport lists one file's entries and the problems found in it
+type FileReport struct {
+	File    string  `json:"file"`
+	Entries int     `json:"entries"`
+	Error   string  `json:"error,omitempty"`
+	Issues  []Issue `json:"issues"`
+}
+
+// newReport tallies the per-file load results and the deduplicated corpus
+func newReport(src Source, path string, results []fileResult, c *corpus) *Report {
+	report := &Report{
+		Source:               src.Name(),
+		Path:                 path,
+		UniqueWords:          c.count,
+		DuplicatesMerged:     c.duplicates,
+		DuplicateDefinitions: c.duplicateDefinitions,
+		UnknownPartsOfSpeech: make(map[string]int),
+		Files:                make([]FileReport, 0, len(results)),
+	}
+
+	for _, result := range results {
+		file := FileReport{File: result.file, Entries: result.count, Issues: []Issue{}}
+		if result.err != nil {
+			file.Error = result.err.Error()
+		}
+		if result.index != nil {
+			file.Issues = append(file.Issues, result.index.issues...)
+		}
+
+		for _, issue := range file.Issues {
+			switch issue.Kind {
+			case issueEmptyDefinition:
+				report.EmptyDefinitions++
+			case issueMalformedDefinition:
+				report.MalformedDefinitions++
+			case issueParseError:
+				report.ParseErrors++
+			case issueUnknownPartOfSpeech:
+				report.UnknownPartsOfSpeech[issue.Value]++
+			}
+		}
+
+		if result.err == nil {
+			// Files that failed to load aren't imported, so their entries don't count
+			report.Entries += result.count
+		}
+		report.Files = append(report.Files, file)
+	}
+
+	return report
+}
+
+// countExisting sets how many of the corpus's words are already in the database, looking
+// each one up rather than loading the database's words into memory. A nil db counts every
+// word as new.
+func (r *Report) countExisting(db *sql.DB, c *corpus) error {
+	r.NewWords, r.ExistingWords = c.count, 0
+	if db == nil {
+		return nil
+	}
+
+	stmt, err := db.Prepare("SELECT EXISTS(SELECT 1 FROM words WHERE word = ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	words, err := c.open()
+	if err != nil {
+		return err
+	}
+	defer words.Close()
+
+	for {
+		word, err := words.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var present bool
+		if err := stmt.QueryRow(word.Word).Scan(&present); err != nil {
+			return err
+		}
+		if present {
+			r.ExistingWords++
+		}
+	}
+	r.NewWords -= r.ExistingWords
+	return nil
+}
+
+// write saves the report as indented JSON to path, or to stdout if path is "-"
+func (r *Report) write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// openReadOnly opens an existing database without creating or migrating it. It returns nil
+// if the file doesn't exist yet.
+func openReadOnly(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReportCounts(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "fruit.jsonl")
+	content := `{"word": "apple", "pos": "noun", "senses": [{"glosses": ["a round fruit"]}]}
+{"word": "apple", "pos": "noun", "senses": [{"glosses": ["A round  fruit"]}, {"glosses": ["the tree bearing it"]}]}
+{"word": "banana", "pos": "noun", "senses": [{"glosses": ["   "]}]}
+{"word": "cherry", "pos": "noun", "senses": [{"glosses": ["a small red fruit (with a stone"]}]}
+{"word": "damson", "pos": "noun"
+{"word": "date", "pos": "noun", "senses": [{"glosses": ["a sweet brown fruit"]}]}
+`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, results, spill, err := loadCorpus(wiktionarySource{}, []string{file}, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spill != nil {
+		defer spill.Close()
+	}
+	report := newReport(wiktionarySource{}, file, results, c)
+
+	db := openTestDB(t)
+	if _, err := db.Exec("INSERT INTO words (word) VALUES ('date')"); err != nil {
+		t.Fatal(err)
+	}
+	if err := report.countExisting(db, c); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, count := range []struct {
+		name      string
+		got, want int
+	}{
+		{"entries", report.Entries, 5},
+		{"unique words", report.UniqueWords, 3},
+		{"new words", report.NewWords, 2},
+		{"existing words", report.ExistingWords, 1},
+		{"duplicates merged", report.DuplicatesMerged, 1},
+		{"duplicate definitions", report.DuplicateDefinitions, 1},
+		{"empty definitions", report.EmptyDefinitions, 1},
+		{"malformed definitions", report.MalformedDefinitions, 1},
+		{"parse errors", report.ParseErrors, 1},
+	} {
+		if count.got != count.want {
+			t.Errorf("%s = %d, want %d", count.name, count.got, count.want)
+		}
+	}
+	if len(report.Files) != 1 || len(report.Files[0].Issues) != 4 {
+		t.Errorf("files %+v, want one file with 4 issues", report.Files)
+	}
+
+	// Without a database every word is new
+	if err := report.countExisting(nil, c); err != nil {
+		t.Fatal(err)
+	}
+	if report.NewWords != 3 || report.ExistingWords != 0 {
+		t.Errorf("without a database: %d new, %d existing; want 3 new", report.NewWords, report.ExistingWords)
+	}
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Import run statuses
+const (
+	runRunning     = "running"
+	runCompleted   = "completed"
+	runFailed      = "failed"
+	runInterrupted = "interrupted"
+	runRolledBack  = "rolled_back"
+)
+
+// errInterrupted stops an import between batches; the run can be resumed later
+var errInterrupted = errors.New("import interrupted")
+
+// importRun is a row of import_runs: one invocation of the importer against a set of files,
+// resumable from its last committed batch
+type importRun struct {
+	ID            int64
+	Source        string
+	Path          string
+	Mode          string
+	Files         []runFile
+	Status        string
+	TotalWords    int
+	ImportedWords int
+	FailedWords   int
+	LastWord      string
+	Error         string
+	StartedAt     time.Time
+	UpdatedAt     time.Time
+	FinishedAt    *time.Time
+}
+
+// runFile fingerprints a source file, so a resumed run can tell the data hasn't changed
+type runFile struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// fingerprintFiles records the size and modification time of each file
+func fingerprintFiles(files []string) ([]runFile, error) {
+	fingerprints := make([]runFile, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		fingerprints = append(fingerprints, runFile{Path: file, Size: info.Size(), ModTime: info.ModTime().UTC()})
+	}
+	return fingerprints, nil
+}
+
+// sameFiles reports whether two fingerprints describe the same files
+func sameFiles(a, b []runFile) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Path != b[i].Path || a[i].Size != b[i].Size || !a[i].ModTime.Equal(b[i].ModTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// startRun records a new run before its first batch is written
+func startRun(db *sql.DB, source, path, mode string, files []runFile, totalWords int) (*importRun, error) {
+	data, err := json.Marshal(files)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result, err := db.Exec(`
+		INSERT INTO import_runs (source, path, mode, files, status, total_words, started_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, source, path, mode, string(data), runRunning, totalWords, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return loadRun(db, id)
+}
+
+const runColumns = `id, source, path, mode, files, status, total_words, imported_words, failed_words,
+	last_word, error, started_at, updated_at, finished_at`
+
+func scanRun(row interface{ Scan(...interface{}) error }) (*importRun, error) {
+	var run importRun
+	var files string
+	var lastWord, runError sql.NullString
+	var finishedAt sql.NullTime
+
+	err := row.Scan(&run.ID, &run.Source, &run.Path, &run.Mode, &files, &run.Status,
+		&run.TotalWords, &run.ImportedWords, &run.FailedWords,
+		&lastWord, &runError, &run.StartedAt, &run.UpdatedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(files), &run.Files); err != nil {
+		return nil, fmt.Errorf("run %d has unreadable file list: %w", run.ID, err)
+	}
+	run.LastWord = lastWord.String
+	run.Error = runError.String
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	return &run, nil
+}
+
+func loadRun(db *sql.DB, id int64) (*importRun, error) {
+	run, err := scanRun(db.QueryRow("SELECT "+runColumns+" FROM import_runs WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("import run %d not found", id)
+	}
+	return run, err
+}
+
+// listRuns returns the most recent runs first
+func listRuns(db *sql.DB, limit int) ([]*importRun, error) {
+	rows, err := db.Query("SELECT "+runColumns+" FROM import_runs ORDER BY id DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*importRun
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// lastBatch returns the number of the run's last committed batch, or 0 if none was committed
+func lastBatch(db *sql.DB, runID int64) (int, error) {
+	var batch sql.NullInt64
+	err := db.QueryRow("SELECT MAX(batch) FROM import_checkpoints WHERE run_id = ?", runID).Scan(&batch)
+	return int(batch.Int64), err
+}
+
+// checkpoint records a batch inside its own transaction, so the run's progress is exactly
+// what has been committed
+func (b *importBatch) checkpoint(batch int, first, last string, saved, failed int) error {
+	now := time.Now()
+	if _, err := b.tx.Exec(`
+		INSERT INTO import_checkpoints (run_id, batch, first_word, last_word, imported, failed, committed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, b.runID, batch, first, last, saved, failed, now); err != nil {
+		return err
+	}
+
+	_, err := b.tx.Exec(`
+		UPDATE import_runs
+		SET imported_words = imported_words + ?, failed_words = failed_words + ?, last_word = ?, updated_at = ?
+		WHERE id = ?
+	`, saved, failed, last, now, b.runID)
+	return err
+}
+
+// finishRun sets the run's final status, with the error that ended it if any
+func finishRun(db *sql.DB, runID int64, status string, runErr error) error {
+	now := time.Now()
+	var message sql.NullString
+	if runErr != nil {
+		message = sql.NullString{String: runErr.Error(), Valid: true}
+	}
+
+	var finishedAt interface{}
+	if status == runCompleted || status == runRolledBack {
+		finishedAt = now
+	}
+
+	_, err := db.Exec(`
+		UPDATE import_runs SET status = ?, error = ?, updated_at = ?, finished_at = ? WHERE id = ?
+	`, status, message, now, finishedAt, runID)
+	return err
+}
+
+// resumeRun checks that a run can continue with the given source and files and marks it
+// running again
+func resumeRun(db *sql.DB, id int64, source, path string, files []runFile) (*importRun, error) {
+	run, err := loadRun(db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case run.Status == runCompleted || run.Status == runRolledBack:
+		return nil, fmt.Errorf("import run %d is %s and can't be resumed", id, run.Status)
+	case run.Source != source:
+		return nil, fmt.Errorf("import run %d read %s data, not %s", id, run.Source, source)
+	case run.Path != path:
+		return nil, fmt.Errorf("import run %d read %s, not %s", id, run.Path, path)
+	case !sameFiles(run.Files, files):
+		return nil, fmt.Errorf("source files changed since import run %d started; start a new import instead", id)
+	}
+
+	if _, err := db.Exec("UPDATE import_runs SET status = ?, error = NULL, updated_at = ? WHERE id = ?",
+		runRunning, time.Now(), id); err != nil {
+		return nil, err
+	}
+	run.Status = runRunning
+	return run, nil
+}
+
+// printRuns writes a table of recent runs
+func printRuns(runs []*importRun) {
+	if len(runs) == 0 {
+		fmt.Println("No imports recorded yet")
+		return
+	}
+
+	fmt.Printf("%-5s %-11s %-7s %-12s %-15s %-7s %-20s %-19s %s\n",
+		"ID", "SOURCE", "MODE", "STATUS", "WORDS", "FAILED", "LAST WORD", "UPDATED", "PATH")
+	for _, run := range runs {
+		fmt.Printf("%-5d %-11s %-7s %-12s %-15s %-7d %-20s %-19s %s\n",
+			run.ID, run.Source, run.Mode, run.Status,
+			fmt.Sprintf("%d/%d", run.ImportedWords, run.TotalWords), run.FailedWords,
+			truncate(run.LastWord, 20), run.UpdatedAt.Local().Format("2006-01-02 15:04:05"), run.Path)
+		if run.Error != "" {
+			fmt.Printf("      error: %s\n", run.Error)
+		}
+	}
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// rollbackWords lists the words a run created, as a subquery taking the run ID
+const rollbackWords = "SELECT id FROM words WHERE import_run_id = ?"
+
+// referencedWords lists words that users have studied, annotated or proposed changes to, and
+// words with mined corpus examples
+const referencedWords = `
+	SELECT word_id FROM user_words
+	UNION SELECT word_id FROM review_history
+	UNION SELECT word_id FROM review_session_cards
+	UNION SELECT word_id FROM user_word_notes
+	UNION SELECT word_id FROM user_word_examples
+	UNION SELECT word_id FROM pending_changes
+	UNION SELECT word_id FROM corpus_examples`
+
+// rollbackRun removes the rows a run inserted, in one transaction. Words it created are
+// deleted with everything attached to them, except words users have since studied or
+// annotated, which are kept and detached from the run. Rows a merge added to existing words
+// are deleted from them. An update run's changes to existing words can't be undone, since the
+// rows it replaced are gone, so only the words it created are removed.
+func rollbackRun(db *sql.DB, id int64) (int64, error) {
+	run, err := loadRun(db, id)
+	if err != nil {
+		return 0, err
+	}
+	if run.Status == runCompleted || run.Status == runRolledBack {
+		return 0, fmt.Errorf("import run %d is %s and can't be rolled back", id, run.Status)
+	}
+	if run.Status == runRunning && time.Since(run.UpdatedAt) < time.Minute {
+		return 0, fmt.Errorf("import run %d is still running", id)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Keep referenced words: detach them and their rows from the run
+	keptWords := "SELECT id FROM words WHERE import_run_id = ? AND id IN (" + referencedWords + ")"
+	keptMeanings := "SELECT id FROM meanings WHERE word_id IN (" + keptWords + ")"
+	keptDefinitions := "SELECT id FROM definitions WHERE meaning_id IN (" + keptMeanings + ")"
+	for _, query := range []string{
+		"UPDATE synonyms SET import_run_id = NULL WHERE import_run_id = ? AND (meaning_id IN (" + keptMeanings + ") OR definition_id IN (" + keptDefinitions + "))",
+		"UPDATE antonyms SET import_run_id = NULL WHERE import_run_id = ? AND (meaning_id IN (" + keptMeanings + ") OR definition_id IN (" + keptDefinitions + "))",
+		"UPDATE definitions SET import_run_id = NULL WHERE import_run_id = ? AND id IN (" + keptDefinitions + ")",
+		"UPDATE meanings SET import_run_id = NULL WHERE import_run_id = ? AND id IN (" + keptMeanings + ")",
+		"UPDATE phonetics SET import_run_id = NULL WHERE import_run_id = ? AND word_id IN (" + keptWords + ")",
+		"UPDATE source_urls SET import_run_id = NULL WHERE import_run_id = ? AND word_id IN (" + keptWords + ")",
+		"UPDATE word_credits SET import_run_id = NULL WHERE import_run_id = ? AND word_id IN (" + keptWords + ")",
+		"UPDATE words SET import_run_id = NULL WHERE id IN (" + keptWords + ")",
+	} {
+		if _, err := tx.Exec(query, runArgs(query, id)...); err != nil {
+			return 0, err
+		}
+	}
+
+	// Existing words the run merged rows into are touched so cached lookups revalidate
+	if run.Mode != modeUpdate {
+		touch := `
+			UPDATE words SET updated_at = CURRENT_TIMESTAMP
+			WHERE (import_run_id IS NULL OR import_run_id != ?) AND id IN (
+				SELECT word_id FROM meanings WHERE import_run_id = ?
+				UNION SELECT m.word_id FROM definitions d JOIN meanings m ON m.id = d.meaning_id WHERE d.import_run_id = ?
+				UNION SELECT m.word_id FROM synonyms s JOIN meanings m ON m.id = s.meaning_id WHERE s.import_run_id = ?
+				UNION SELECT m.word_id FROM synonyms s JOIN definitions d ON d.id = s.definition_id JOIN meanings m ON m.id = d.meaning_id WHERE s.import_run_id = ?
+				UNION SELECT m.word_id FROM antonyms a JOIN meanings m ON m.id = a.meaning_id WHERE a.import_run_id = ?
+				UNION SELECT m.word_id FROM antonyms a JOIN definitions d ON d.id = a.definition_id JOIN meanings m ON m.id = d.meaning_id WHERE a.import_run_id = ?
+				UNION SELECT word_id FROM phonetics WHERE import_run_id = ?
+				UNION SELECT word_id FROM source_urls WHERE import_run_id = ?
+				UNION SELECT word_id FROM word_credits WHERE import_run_id = ?
+			)`
+		if _, err := tx.Exec(touch, runArgs(touch, id)...); err != nil {
+			return 0, err
+		}
+	}
+
+	// An update run's rows on existing words replaced earlier ones, so they stay
+	tagged := "import_run_id = ?"
+	if run.Mode == modeUpdate {
+		tagged = "0"
+	}
+	meanings := "SELECT id FROM meanings WHERE " + tagged + " OR word_id IN (" + rollbackWords + ")"
+	definitions := "SELECT id FROM definitions WHERE " + tagged + " OR meaning_id IN (" + meanings + ")"
+	for _, query := range []string{
+		// Foreign keys aren't enforced, so references to deleted definitions are cleared by hand
+		"UPDATE user_word_notes SET preferred_definition_id = NULL WHERE preferred_definition_id IN (" + definitions + ")",
+		"UPDATE pending_changes SET definition_id = NULL WHERE definition_id IN (" + definitions + ")",
+		"DELETE FROM synonyms WHERE " + tagged + " OR meaning_id IN (" + meanings + ") OR definition_id IN (" + definitions + ")",
+		"DELETE FROM antonyms WHERE " + tagged + " OR meaning_id IN (" + meanings + ") OR definition_id IN (" + definitions + ")",
+		"DELETE FROM definitions WHERE id IN (" + definitions + ")",
+		"DELETE FROM meanings WHERE id IN (" + meanings + ")",
+		"DELETE FROM phonetics WHERE " + tagged + " OR word_id IN (" + rollbackWords + ")",
+		"DELETE FROM source_urls WHERE " + tagged + " OR word_id IN (" + rollbackWords + ")",
+		"DELETE FROM word_credits WHERE " + tagged + " OR word_id IN (" + rollbackWords + ")",
+	} {
+		if _, err := tx.Exec(query, runArgs(query, id)...); err != nil {
+			return 0, err
+		}
+	}
+
+	result, err := tx.Exec("DELETE FROM words WHERE import_run_id = ?", id)
+	if err != nil {
+		return 0, err
+	}
+	deletedWords, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE import_runs SET status = ?, updated_at = ?, finished_at = ? WHERE id = ?
+	`, runRolledBack, time.Now(), time.Now(), id); err != nil {
+		return 0, err
+	}
+	return deletedWords, tx.Commit()
+}
+
+// runArgs passes the run ID for every placeholder in query
+func runArgs(query string, id int64) []interface{} {
+	args := make([]interface{}, strings.Count(query, "?"))
+	for i := range args {
+		args[i] = id
+	}
+	return args
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// dictionaryTables are the tables an import writes to
+var dictionaryTables = []string{"words", "meanings", "definitions", "synonyms", "antonyms", "phonetics", "source_urls", "word_credits"}
+
+// tableCounts returns the number of rows in each dictionary table
+func tableCounts(t *testing.T, db *sql.DB) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	for _, table := range dictionaryTables {
+		counts[table] = countRows(t, db, table)
+	}
+	return counts
+}
+
+// storedWords returns the words in the database in order
+func storedWords(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	rows, err := db.Query("SELECT word FROM words ORDER BY word")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var words []string
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			t.Fatal(err)
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+func TestRollbackRun(t *testing.T) {
+	db := openTestDB(t)
+	importWords(t, db, modeSkip, "wordset",
+		testWord("apple", "noun", []string{"pome"}, models.Definition{Definition: "a round fruit"}),
+		testWord("pear", "noun", nil, models.Definition{Definition: "a sweet fruit"}),
+	)
+	before := tableCounts(t, db)
+
+	// A merge run that creates words and adds rows to an existing one, then fails
+	apple := testWord("apple", "noun", []string{"malus"},
+		models.Definition{Definition: "the tree bearing apples", Synonyms: []string{"apple tree"}})
+	apple.Phonetics = []models.Phonetic{{Text: "/ˈæp.əl/"}}
+	apple.SourceUrls = []string{"https://example.org/apple"}
+	runID := importWords(t, db, modeMerge, "opted",
+		apple,
+		testWord("plum", "noun", []string{"prune"}, models.Definition{Definition: "a stone fruit", Antonyms: []string{"lemon"}}),
+		testWord("quince", "noun", nil, models.Definition{Definition: "a hard fruit"}),
+	)
+	if err := finishRun(db, runID, runFailed, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := rollbackRun(db, runID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Errorf("removed %d words, want 2", deleted)
+	}
+	if after := tableCounts(t, db); !reflect.DeepEqual(after, before) {
+		t.Errorf("rows after rollback %v, want %v", after, before)
+	}
+	if definitions := storedDefinitions(t, db, "apple"); len(definitions) != 1 || definitions[0].text != "a round fruit" {
+		t.Errorf("apple's definitions after rollback: %+v", definitions)
+	}
+
+	run, err := loadRun(db, runID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != runRolledBack {
+		t.Errorf("run is %s, want %s", run.Status, runRolledBack)
+	}
+	if _, err := rollbackRun(db, runID); err == nil {
+		t.Error("rolled back a run twice")
+	}
+}
+
+func TestRollbackRunKeepsReferencedWords(t *testing.T) {
+	db := openTestDB(t)
+	runID := importWords(t, db, modeSkip, "wordset",
+		testWord("plum", "noun", nil, models.Definition{Definition: "a stone fruit"}),
+		testWord("quince", "noun", nil, models.Definition{Definition: "a hard fruit"}),
+	)
+	if err := finishRun(db, runID, runInterrupted, nil); err != nil {
+		t.Fatal(err)
+	}
+	// Someone studies plum before the run is rolled back
+	if _, err := db.Exec(`
+		INSERT INTO user_words (user_id, word_id, next_review_date)
+		SELECT 1, id, CURRENT_TIMESTAMP FROM words WHERE word = 'plum'
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := rollbackRun(db, runID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Errorf("removed %d words, want 1", deleted)
+	}
+	if words := storedWords(t, db); !reflect.DeepEqual(words, []string{"plum"}) {
+		t.Errorf("words after rollback %v, want [plum]", words)
+	}
+	if definitions := storedDefinitions(t, db, "plum"); len(definitions) != 1 {
+		t.Errorf("plum kept %d definitions, want 1", len(definitions))
+	}
+	var tagged int
+	if err := db.QueryRow("SELECT COUNT(*) FROM words WHERE import_run_id IS NOT NULL").Scan(&tagged); err != nil {
+		t.Fatal(err)
+	}
+	if tagged != 0 {
+		t.Error("the kept word is still tagged with the rolled back run")
+	}
+}
+
+func TestResumeRun(t *testing.T) {
+	db := openTestDB(t)
+	var words []*models.Word
+	for _, word := range []string{"ash", "beech", "cedar", "elm", "fir", "larch", "oak"} {
+		words = append(words, testWord(word, "noun", nil, models.Definition{Definition: "a tree"}))
+	}
+	c := &corpus{words: words, count: len(words)}
+
+	// The first run commits two batches of two words and is interrupted
+	run, err := startRun(db, "wordset", "trees", modeSkip, nil, c.count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := importOptions{Mode: modeSkip, Source: "wordset", BatchSize: 2, FirstBatch: 1, RunID: run.ID}
+	partial := &corpus{words: words[:4], count: 4}
+	stream, _ := partial.open()
+	if err := importToDatabase(context.Background(), db, stream, c.count, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := finishRun(db, run.ID, runInterrupted, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resume as -resume does: after the last checkpoint, numbering batches on
+	if _, err := resumeRun(db, run.ID, "opted", "trees", nil); err == nil {
+		t.Error("resumed a run with another source")
+	}
+	run, err = resumeRun(db, run.ID, "wordset", "trees", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.LastWord != "elm" || run.ImportedWords != 4 {
+		t.Fatalf("run stopped after %q with %d words, want elm with 4", run.LastWord, run.ImportedWords)
+	}
+	last, err := lastBatch(db, run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.After, opts.FirstBatch = run.LastWord, last+1
+
+	stream, _ = c.open()
+	if err := importToDatabase(context.Background(), db, stream, c.count-run.ImportedWords, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := finishRun(db, run.ID, runCompleted, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := storedWords(t, db); !reflect.DeepEqual(got, []string{"ash", "beech", "cedar", "elm", "fir", "larch", "oak"}) {
+		t.Errorf("words after resuming %v", got)
+	}
+	if n := countRows(t, db, "definitions"); n != len(words) {
+		t.Errorf("%d definitions, want %d", n, len(words))
+	}
+
+	rows, err := db.Query("SELECT batch, first_word, last_word FROM import_checkpoints WHERE run_id = ? ORDER BY id", run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var checkpoints []string
+	for rows.Next() {
+		var batch int
+		var first, last string
+		if err := rows.Scan(&batch, &first, &last); err != nil {
+			t.Fatal(err)
+		}
+		checkpoints = append(checkpoints, fmt.Sprintf("%d %s-%s", batch, first, last))
+	}
+	want := []string{"1 ash-beech", "2 cedar-elm", "3 fir-larch", "4 oak-oak"}
+	if !reflect.DeepEqual(checkpoints, want) {
+		t.Errorf("checkpoints %v, want %v", checkpoints, want)
+	}
+
+	run, err = loadRun(db, run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.ImportedWords != len(words) || run.Status != runCompleted {
+		t.Errorf("run %s with %d words imported, want completed with %d", run.Status, run.ImportedWords, len(words))
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Source reads one dictionary dataset format and feeds its entries into a WordIndex
+type Source interface {
+	// Name identifies the source on the command line
+	Name() string
+	// Files lists the input files at path, which may be a file or a directory
+	Files(path string) ([]string, error)
+	// Load parses one file into the index and returns the number of entries read
+	Load(file string, index *WordIndex) (int, error)
+}
+
+// sources lists the available adapters by name
+var sources = map[string]Source{
+	"wordset":    wordsetSource{},
+	"opted":      optedSource{},
+	"wordnet":    wordnetSource{},
+	"wiktionary": wiktionarySource{},
+	// Formats written by cmd/exporter (wordset and opted CSV are read by the adapters above)
+	"jsonl":         jsonlSource{},
+	"dictionaryapi": dictionaryAPISource{},
+}
+
+// sourceNames returns the adapter names in alphabetical order, for usage text
+func sourceNames() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fileResult is the outcome of loading one file
+type fileResult struct {
+	file  string
+	count int
+	err   error
+	index *WordIndex
+}
+
+// loadFiles parses files concurrently, each into its own index from newIndex, and hands the
+// results to fn in file order so they can be merged deterministically. At most workers files
+// are read ahead of fn, which bounds the memory held by finished indexes. The results are
+// returned once fn has seen them all.
+func loadFiles(src Source, files []string, workers int, newIndex func() *WordIndex, fn func(int, fileResult)) []fileResult {
+	workers = max(workers, 1)
+	results := make([]fileResult, len(files))
+	done := make([]chan struct{}, len(files))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	jobs := make(chan int)
+	slots := make(chan struct{}, workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				index := newIndex()
+				count, err := src.Load(files[i], index)
+				if err == nil {
+					err = index.spillErr
+				}
+				results[i] = fileResult{file: files[i], count: count, err: err, index: index}
+				close(done[i])
+			}
+		}()
+	}
+
+	go func() {
+		for i := range files {
+			slots <- struct{}{}
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	for i := range files {
+		<-done[i]
+		fn(i, results[i])
+		<-slots
+	}
+	return results
+}
+
+// loadCorpus loads and deduplicates every file. With maxWords above zero, at most about that
+// many words are held in memory: past it, indexes spill sorted runs to a temporary directory
+// that is merged into a single file once loading is done. The spiller returned, if any, owns
+// that file and must be closed after the import.
+func loadCorpus(src Source, files []string, workers, maxWords int) (*corpus, []fileResult, *spiller, error) {
+	workers = max(workers, 1)
+	index := NewWordIndex()
+	var spill *spiller
+	if maxWords > 0 {
+		var err error
+		if spill, err = newSpiller(); err != nil {
+			return nil, nil, nil, err
+		}
+		// Half the budget for the merged index, half shared by the files being parsed
+		index.spill, index.limit = spill, max(maxWords/2, 1)
+	}
+	newIndex := func() *WordIndex {
+		idx := NewWordIndex()
+		if spill != nil {
+			idx.spill, idx.limit = spill, max(maxWords/(2*workers), 1)
+		}
+		return idx
+	}
+
+	results := loadFiles(src, files, workers, newIndex, func(i int, result fileResult) {
+		if result.err != nil {
+			log.Printf("Error loading %s: %v", result.file, result.err)
+			if spill != nil {
+				spill.discard(result.index.runs...)
+			}
+			result.index.words = nil
+			return
+		}
+		if spill != nil {
+			spill.keep(result.index.runs...)
+		}
+		index.Merge(result.index)
+		if spill != nil {
+			spill.keep(index.runs...)
+			index.runs = nil
+		}
+		// Only the issues are needed from here on, for the report
+		result.index.words = nil
+		fmt.Fprintf(out, "  [%d/%d] %s: %d entries\n", i+1, len(files), filepath.Base(result.file), result.count)
+	})
+	if index.spillErr != nil {
+		return nil, results, spill, index.spillErr
+	}
+
+	c := &corpus{duplicates: index.duplicates, duplicateDefinitions: index.duplicateDefinitions}
+	if spill == nil || len(spill.runs) == 0 {
+		c.words = index.GetAll()
+		c.count = len(c.words)
+		return c, results, spill, nil
+	}
+
+	if len(index.words) > 0 {
+		index.flush()
+		if index.spillErr != nil {
+			return nil, results, spill, index.spillErr
+		}
+		spill.keep(index.runs...)
+	}
+	index.words = nil
+	fmt.Fprintf(out, "💾 Merging %d sorted runs spilled to disk...\n", len(spill.runs))
+	if err := spill.merge(c); err != nil {
+		return nil, results, spill, err
+	}
+	return c, results, spill, nil
+}
+
+// globFiles returns path itself if it is a file, or the files in the directory matching the patterns
+func globFiles(path string, patterns ...string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(path, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s files found in %s", strings.Join(patterns, ", "), path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// normalizePartOfSpeech maps the abbreviations used by various datasets to the
+// names Wordset and dictionaryapi.dev use. Unknown values are returned lowercased.
+func normalizePartOfSpeech(pos string) string {
+	key := strings.ToLower(strings.TrimSpace(pos))
+	if name, ok := partsOfSpeech[key]; ok {
+		return name
+	}
+	return key
+}
+
+var partsOfSpeech = map[string]string{
+	// WordNet synset types
+	"n": "noun", "v": "verb", "a": "adjective", "s": "adjective", "r": "adverb",
+	// Wiktionary extract
+	"adj": "adjective", "adv": "adverb", "intj": "interjection", "prep": "preposition",
+	"pron": "pronoun", "conj": "conjunction", "det": "determiner", "num": "numeral",
+	"name": "proper noun",
+	// OPTED (Webster's 1913)
+	"n.": "noun", "n. pl.": "noun", "pl.": "noun", "n. sing.": "noun",
+	"v.": "verb", "v. t.": "verb", "v. i.": "verb", "imp.": "verb", "p. p.": "verb", "p. pr.": "verb",
+	"imp. & p. p.": "verb", "p. p. & a.": "adjective", "p. a.": "adjective",
+	"a.": "adjective", "adv.": "adverb", "prep.": "preposition", "conj.": "conjunction",
+	"interj.": "interjection", "pron.": "pronoun", "article": "article", "definite article": "article",
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// sourceTest is a source file's content and the entries and issues it should produce
+type sourceTest struct {
+	name    string
+	content string
+	words   []string // as summarized by entrySummary
+	issues  []string // issue kinds
+	count   int      // entries read
+}
+
+// runSourceTests loads each test's content with src and compares what the index holds, in
+// sorted order
+func runSourceTests(t *testing.T, src Source, fileName string, tests []sourceTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), fileName)
+			if err := os.WriteFile(file, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			index := NewWordIndex()
+			count, err := src.Load(file, index)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if count != tt.count {
+				t.Errorf("read %d entries, want %d", count, tt.count)
+			}
+
+			var words []string
+			for _, word := range index.GetAll() {
+				words = append(words, entrySummary(word)...)
+			}
+			sort.Strings(words)
+			if !reflect.DeepEqual(words, tt.words) {
+				t.Errorf("entries:\n%s\nwant:\n%s", strings.Join(words, "\n"), strings.Join(tt.words, "\n"))
+			}
+
+			var issues []string
+			for _, issue := range index.issues {
+				issues = append(issues, issue.Kind)
+			}
+			if !reflect.DeepEqual(issues, tt.issues) {
+				t.Errorf("issues %v, want %v", issues, tt.issues)
+			}
+		})
+	}
+}
+
+// entrySummary describes each of a word's definitions on one line as
+// word|part of speech|definition|example|synonyms, with the meaning's synonyms after a slash
+func entrySummary(word *models.Word) []string {
+	var lines []string
+	for _, m := range word.Meanings {
+		for _, d := range m.Definitions {
+			synonyms := strings.Join(d.Synonyms, ",")
+			if len(m.Synonyms) > 0 {
+				synonyms += "/" + strings.Join(m.Synonyms, ",")
+			}
+			lines = append(lines, strings.Join([]string{word.Word, m.PartOfSpeech, d.Definition, d.Example, synonyms}, "|"))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// spiller keeps the memory an import needs bounded. Indexes that grow past their limit write
+// their words to disk as runs sorted by key, and merge folds the runs into one sorted,
+// deduplicated file that phase 2 streams from.
+type spiller struct {
+	dir  string
+	mu   sync.Mutex
+	next int
+	// runs are the kept runs, in the order their words were read
+	runs []string
+}
+
+func newSpiller() (*spiller, error) {
+	dir, err := os.MkdirTemp("", "words-import")
+	if err != nil {
+		return nil, err
+	}
+	return &spiller{dir: dir}, nil
+}
+
+// write saves words, which must be sorted by key, as a new run and returns its path. The run
+// is only merged once it is passed to keep.
+func (s *spiller) write(words []*models.Word) (string, error) {
+	s.mu.Lock()
+	path := filepath.Join(s.dir, fmt.Sprintf("run-%d.gob", s.next))
+	s.next++
+	s.mu.Unlock()
+
+	return path, writeRun(path, words)
+}
+
+// keep adds runs to the merge. Runs from a file that failed to load are discarded instead.
+func (s *spiller) keep(paths ...string) {
+	s.runs = append(s.runs, paths...)
+}
+
+// discard deletes runs that won't be merged
+func (s *spiller) discard(paths ...string) {
+	for _, path := range paths {
+		os.Remove(path)
+	}
+}
+
+func writeRun(path string, words []*models.Word) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := gob.NewEncoder(w)
+	for _, word := range words {
+		if err := enc.Encode(word); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Close deletes the runs
+func (s *spiller) Close() error {
+	return os.RemoveAll(s.dir)
+}
+
+// merge combines every run into merged.gob, folding words that appear in several runs together
+// as AddOrMerge would. Only one word per run is held at a time. Duplicates found are added to
+// the corpus counts.
+func (s *spiller) merge(c *corpus) error {
+	var h runHeap
+	defer func() {
+		for _, r := range h {
+			r.f.Close()
+		}
+	}()
+	for i, path := range s.runs {
+		r, err := openRun(path, i)
+		if err != nil {
+			return err
+		}
+		if err := r.advance(); err == io.EOF {
+			r.f.Close()
+			continue
+		} else if err != nil {
+			r.f.Close()
+			return err
+		}
+		h = append(h, r)
+	}
+	heap.Init(&h)
+
+	c.file = filepath.Join(s.dir, "merged.gob")
+	f, err := os.Create(c.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	enc := gob.NewEncoder(w)
+
+	var current *models.Word
+	for h.Len() > 0 {
+		r := h[0]
+		word := r.head
+		if err := r.advance(); err == io.EOF {
+			// Free the disk space as soon as a run is used up
+			heap.Pop(&h)
+			r.f.Close()
+			os.Remove(r.f.Name())
+		} else if err != nil {
+			return err
+		} else {
+			heap.Fix(&h, 0)
+		}
+
+		if current != nil && wordKey(current) == wordKey(word) {
+			c.duplicates++
+			c.duplicateDefinitions += foldWord(current, word)
+			continue
+		}
+		if current != nil {
+			if err := enc.Encode(current); err != nil {
+				return err
+			}
+			c.count++
+		}
+		current = word
+	}
+	if current != nil {
+		if err := enc.Encode(current); err != nil {
+			return err
+		}
+		c.count++
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// runReader reads one sorted run back
+type runReader struct {
+	f    *os.File
+	dec  *gob.Decoder
+	head *models.Word
+	// seq breaks ties between runs, so words are folded in the order they were spilled
+	seq int
+}
+
+func openRun(path string, seq int) (*runReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &runReader{f: f, dec: gob.NewDecoder(bufio.NewReader(f)), seq: seq}, nil
+}
+
+// advance reads the next word into head, returning io.EOF at the end of the run
+func (r *runReader) advance() error {
+	word := &models.Word{}
+	if err := r.dec.Decode(word); err != nil {
+		return err
+	}
+	r.head = word
+	return nil
+}
+
+// runHeap orders runs by their next word's key
+type runHeap []*runReader
+
+func (h runHeap) Len() int { return len(h) }
+func (h runHeap) Less(i, j int) bool {
+	ki, kj := wordKey(h[i].head), wordKey(h[j].head)
+	if ki != kj {
+		return ki < kj
+	}
+	return h[i].seq < h[j].seq
+}
+func (h runHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x interface{}) { *h = append(*h, x.(*runReader)) }
+func (h *runHeap) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
+
+// corpus is the deduplicated result of phase 1: the words in key order, either in memory or,
+// when they didn't fit, in a merged run on disk
+type corpus struct {
+	words []*models.Word
+	file  string
+
+	count                int
+	duplicates           int
+	duplicateDefinitions int
+}
+
+// open starts reading the words from the beginning; a corpus can be read more than once
+func (c *corpus) open() (*wordStream, error) {
+	if c.file == "" {
+		return &wordStream{words: c.words}, nil
+	}
+	f, err := os.Open(c.file)
+	if err != nil {
+		return nil, err
+	}
+	return &wordStream{f: f, dec: gob.NewDecoder(bufio.NewReader(f))}, nil
+}
+
+// wordStream yields a corpus's words one at a time
+type wordStream struct {
+	words []*models.Word
+	f     *os.File
+	dec   *gob.Decoder
+}
+
+// next returns the next word, or io.EOF after the last one
+func (s *wordStream) next() (*models.Word, error) {
+	if s.f == nil {
+		if len(s.words) == 0 {
+			return nil, io.EOF
+		}
+		word := s.words[0]
+		s.words = s.words[1:]
+		return word, nil
+	}
+
+	word := &models.Word{}
+	if err := s.dec.Decode(word); err != nil {
+		return nil, err
+	}
+	return word, nil
+}
+
+func (s *wordStream) Close() error {
+	if s.f != nil {
+		return s.f.Close()
+	}
+	return nil
+}
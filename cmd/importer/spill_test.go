@@ -0,0 +1,118 @@
+package main
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// memorySource serves entries written as "word: definition" from memory, one slice per file
+type memorySource map[string][]string
+
+func (memorySource) Name() string { return "memory" }
+
+func (s memorySource) Files(string) ([]string, error) {
+	files := make([]string, 0, len(s))
+	for file := range s {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (s memorySource) Load(file string, index *WordIndex) (int, error) {
+	for _, entry := range s[file] {
+		word, definition, _ := strings.Cut(entry, ": ")
+		index.Add(Location{File: file, Key: word}, testWord(word, "noun", nil, models.Definition{Definition: definition}))
+	}
+	return len(s[file]), nil
+}
+
+// corpusSummary reads a corpus back as "word: definition; definition" lines
+func corpusSummary(t *testing.T, c *corpus) []string {
+	t.Helper()
+	stream, err := c.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	var lines []string
+	for {
+		word, err := stream.next()
+		if err == io.EOF {
+			return lines
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var definitions []string
+		for _, m := range word.Meanings {
+			for _, d := range m.Definitions {
+				definitions = append(definitions, d.Definition)
+			}
+		}
+		lines = append(lines, word.Word+": "+strings.Join(definitions, "; "))
+	}
+}
+
+func TestSpilledCorpus(t *testing.T) {
+	src := memorySource{
+		"a": {"cherry: a small red fruit", "apple: a round fruit", "banana: a long fruit", "apple: the tree bearing apples"},
+		"b": {"elder: a shrub", "banana: a plant of the tropics", "apple: a round fruit", "date: a sweet fruit"},
+		"c": {"grape: a vine fruit", "cherry: the tree bearing cherries", "fig: a soft fruit"},
+	}
+	files, _ := src.Files("")
+	want := []string{
+		"apple: a round fruit; the tree bearing apples",
+		"banana: a long fruit; a plant of the tropics",
+		"cherry: a small red fruit; the tree bearing cherries",
+		"date: a sweet fruit",
+		"elder: a shrub",
+		"fig: a soft fruit",
+		"grape: a vine fruit",
+	}
+
+	for _, tt := range []struct {
+		name     string
+		maxWords int
+		spilled  bool
+	}{
+		{"in memory", 0, false},
+		// Two words per index, so every file spills runs and so does the merged index
+		{"spilled", 4, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, spill, err := loadCorpus(src, files, 2, tt.maxWords)
+			if spill != nil {
+				defer spill.Close()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if spilled := c.file != ""; spilled != tt.spilled {
+				t.Fatalf("spilled = %v, want %v", spilled, tt.spilled)
+			}
+			if tt.spilled && len(spill.runs) < 4 {
+				t.Errorf("merged %d runs, want several per file", len(spill.runs))
+			}
+
+			if got := corpusSummary(t, c); !reflect.DeepEqual(got, want) {
+				t.Errorf("corpus:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+			if c.count != len(want) || c.duplicates != 4 || c.duplicateDefinitions != 1 {
+				t.Errorf("%d words, %d duplicates, %d duplicate definitions; want %d, 4, 1",
+					c.count, c.duplicates, c.duplicateDefinitions, len(want))
+			}
+
+			// Phase 2 reads the corpus again from the start
+			if got := corpusSummary(t, c); len(got) != len(want) {
+				t.Errorf("second read gave %d words, want %d", len(got), len(want))
+			}
+		})
+	}
+}
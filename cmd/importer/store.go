@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// Import modes decide what happens to words that are already in the database
+const (
+	// modeSkip leaves existing words untouched
+	modeSkip = "skip"
+	// modeMerge adds meanings, definitions, synonyms and phonetics the word doesn't have yet
+	modeMerge = "merge"
+	// modeUpdate replaces the rows this source contributed earlier, leaving other sources' rows intact
+	modeUpdate = "update"
+)
+
+var importModes = []string{modeSkip, modeMerge, modeUpdate}
+
+// importOptions controls how words are written to the database
+type importOptions struct {
+	Mode      string
+	Source    string
+	BatchSize int
+	// RunID tags inserted rows and receives a checkpoint per committed batch
+	RunID int64
+	// FirstBatch numbers the first batch written, so a resumed run continues its checkpoints
+	FirstBatch int
+	// After skips words whose key sorts at or before it, which a resumed run already wrote
+	After string
+}
+
+// importToDatabase writes the stream's words in transactions of BatchSize words, holding only
+// one batch in memory. A word that fails is rolled back on its own and reported; the rest of
+// its batch is still committed. It stops between batches with errInterrupted once ctx is
+// cancelled. total is the number of words expected, for progress.
+func importToDatabase(ctx context.Context, db *sql.DB, words *wordStream, total int, opts importOptions) error {
+	imported := 0
+	errors := 0
+	startTime := time.Now()
+
+	batch := opts.FirstBatch
+	pending := make([]*models.Word, 0, opts.BatchSize)
+	for {
+		if ctx.Err() != nil {
+			fmt.Fprintln(out)
+			return errInterrupted
+		}
+
+		pending = pending[:0]
+		for len(pending) < opts.BatchSize {
+			word, err := words.next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				fmt.Fprintln(out)
+				return fmt.Errorf("reading words: %w", err)
+			}
+			// Words are in key order, so a resumed run skips what it already wrote
+			if opts.After != "" && wordKey(word) <= opts.After {
+				continue
+			}
+			pending = append(pending, word)
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		saved, failed, err := writeBatch(db, pending, batch, opts)
+		if err != nil {
+			fmt.Fprintln(out)
+			return fmt.Errorf("batch starting at '%s': %w", pending[0].Word, err)
+		}
+		imported += saved
+		errors += failed
+		batch++
+
+		// Progress update
+		elapsed := time.Since(startTime)
+		percentage := float64(imported) / float64(total) * 100
+		rate := float64(imported) / elapsed.Seconds()
+		remaining := time.Duration(float64(total-imported)/rate) * time.Second
+
+		fmt.Fprintf(out, "\rProgress: %d/%d (%.1f%%) | Rate: %.0f words/sec | ETA: %s | Errors: %d",
+			imported, total, percentage, rate, remaining.Round(time.Second), errors)
+	}
+
+	fmt.Fprintln(out)
+	return nil
+}
+
+// writeBatch saves words in a single transaction, using a savepoint per word so one bad
+// entry doesn't discard the whole batch. The run's checkpoint is committed with the batch.
+func writeBatch(db *sql.DB, words []*models.Word, batch int, opts importOptions) (int, int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	b := &importBatch{tx: tx, stmts: make(map[string]*sql.Stmt), runID: opts.RunID}
+	now := time.Now()
+	saved, failed := 0, 0
+
+	for _, word := range words {
+		word.CreatedAt = now
+		word.UpdatedAt = now
+
+		if _, err := tx.Exec("SAVEPOINT word"); err != nil {
+			return 0, 0, err
+		}
+		if err := b.saveWord(word, opts); err != nil {
+			log.Printf("Error importing '%s': %v", word.Word, err)
+			if _, err := tx.Exec("ROLLBACK TO word"); err != nil {
+				return 0, 0, err
+			}
+			failed++
+		} else {
+			saved++
+		}
+		if _, err := tx.Exec("RELEASE word"); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err := b.checkpoint(batch, words[0].Word, words[len(words)-1].Word, saved, failed); err != nil {
+		return 0, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return saved, failed, nil
+}
+
+// importBatch is one batch transaction. Statements are prepared on first use and reused for the
+// rest of the batch; the transaction closes them when it ends.
+type importBatch struct {
+	tx    *sql.Tx
+	stmts map[string]*sql.Stmt
+	// runID tags every inserted row with the import run
+	runID int64
+}
+
+func (b *importBatch) prepare(query string) (*sql.Stmt, error) {
+	if stmt, ok := b.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := b.tx.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	b.stmts[query] = stmt
+	return stmt, nil
+}
+
+func (b *importBatch) exec(query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := b.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Exec(args...)
+}
+
+func (b *importBatch) query(query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := b.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Query(args...)
+}
+
+func (b *importBatch) saveWord(word *models.Word, opts importOptions) error {
+	stmt, err := b.prepare("SELECT id FROM words WHERE word = ?")
+	if err != nil {
+		return err
+	}
+
+	var existingID int64
+	err = stmt.QueryRow(word.Word).Scan(&existingID)
+	switch {
+	case err == sql.ErrNoRows:
+		return b.insertWord(word, opts.Source)
+	case err != nil:
+		return err
+	case opts.Mode == modeSkip:
+		return nil
+	case opts.Mode == modeUpdate:
+		if err := b.removeSourceRows(existingID, word, opts.Source); err != nil {
+			return err
+		}
+	}
+	return b.mergeWord(existingID, word, opts.Source)
+}
+
+func (b *importBatch) insertWord(word *models.Word, source string) error {
+	result, err := b.exec(`
+		INSERT INTO words (word, phonetic, wordset_id, import_run_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, word.Word, word.Phonetic, nullString(word.WordsetID), b.runID, word.CreatedAt, word.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	wordID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range word.Phonetics {
+		if err := b.insertPhonetic(wordID, p, source); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range word.Meanings {
+		if err := b.insertMeaning(wordID, m, source); err != nil {
+			return err
+		}
+	}
+
+	for _, url := range word.SourceUrls {
+		if err := b.insertSourceURL(wordID, url, source); err != nil {
+			return err
+		}
+	}
+
+	_, err = b.insertCredits(wordID, word.Credits)
+	return err
+}
+
+func (b *importBatch) insertPhonetic(wordID int64, p models.Phonetic, source string) error {
+	_, err := b.exec(`
+		INSERT INTO phonetics (word_id, text, audio, source, import_run_id) VALUES (?, ?, ?, ?, ?)
+	`, wordID, p.Text, p.Audio, source, b.runID)
+	return err
+}
+
+func (b *importBatch) insertSourceURL(wordID int64, url, source string) error {
+	_, err := b.exec(`
+		INSERT INTO source_urls (word_id, url, source, import_run_id) VALUES (?, ?, ?, ?)
+	`, wordID, url, source, b.runID)
+	return err
+}
+
+// insertCredits adds the credits the word doesn't have yet and returns how many were new
+func (b *importBatch) insertCredits(wordID int64, credits []models.Credit) (int64, error) {
+	added := int64(0)
+	for _, c := range credits {
+		n, err := b.execCount(`
+			INSERT OR IGNORE INTO word_credits (word_id, name, role, source, import_run_id) VALUES (?, ?, ?, ?, ?)
+		`, wordID, c.Name, c.Role, c.Source, b.runID)
+		if err != nil {
+			return added, err
+		}
+		added += n
+	}
+	return added, nil
+}
+
+// insertMeaning inserts a meaning with its synonyms, antonyms and definitions
+func (b *importBatch) insertMeaning(wordID int64, m models.Meaning, source string) error {
+	result, err := b.exec(`
+		INSERT INTO meanings (word_id, part_of_speech, source, import_run_id) VALUES (?, ?, ?, ?)
+	`, wordID, m.PartOfSpeech, source, b.runID)
+	if err != nil {
+		return err
+	}
+
+	meaningID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if err := b.insertRelated("synonyms", "meaning_id", meaningID, m.Synonyms, source); err != nil {
+		return err
+	}
+	if err := b.insertRelated("antonyms", "meaning_id", meaningID, m.Antonyms, source); err != nil {
+		return err
+	}
+
+	for _, d := range m.Definitions {
+		if err := b.insertDefinition(meaningID, d, source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertDefinition inserts a definition with its synonyms and antonyms
+func (b *importBatch) insertDefinition(meaningID int64, d models.Definition, source string) error {
+	result, err := b.exec(`
+		INSERT INTO definitions (meaning_id, definition, example, source, external_id, import_run_id) VALUES (?, ?, ?, ?, ?, ?)
+	`, meaningID, d.Definition, d.Example, source, nullString(d.ExternalID), b.runID)
+	if err != nil {
+		return err
+	}
+
+	definitionID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if err := b.insertRelated("synonyms", "definition_id", definitionID, d.Synonyms, source); err != nil {
+		return err
+	}
+	return b.insertRelated("antonyms", "definition_id", definitionID, d.Antonyms, source)
+}
+
+// insertRelated inserts synonyms or antonyms attached to a meaning or definition
+func (b *importBatch) insertRelated(table, ownerColumn string, ownerID int64, values []string, source string) error {
+	// The column holding the word is the table name without its plural "s"
+	query := fmt.Sprintf("INSERT INTO %s (%s, %s, source, import_run_id) VALUES (?, ?, ?, ?)", table, ownerColumn, table[:len(table)-1])
+	for _, v := range values {
+		if _, err := b.exec(query, ownerID, v, source, b.runID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nullString stores empty optional values as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/words-api/words/internal/database"
+	"github.com/words-api/words/internal/models"
+)
+
+func init() {
+	// Keep progress output out of test results
+	out = io.Discard
+}
+
+// openTestDB opens a migrated in-memory database tuned as the importer tunes it
+func openTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+	db, err := database.InitDB("file:" + tb.Name() + "?mode=memory&cache=shared")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := tuneForImport(db); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	return db
+}
+
+// importWords imports words, which must be sorted by key, as a new run and returns its ID
+func importWords(tb testing.TB, db *sql.DB, mode, source string, words ...*models.Word) int64 {
+	tb.Helper()
+	c := &corpus{words: words, count: len(words)}
+	run, err := startRun(db, source, "test", mode, nil, c.count)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	stream, err := c.open()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer stream.Close()
+
+	opts := importOptions{Mode: mode, Source: source, BatchSize: 100, FirstBatch: 1, RunID: run.ID}
+	if err := importToDatabase(context.Background(), db, stream, c.count, opts); err != nil {
+		tb.Fatal(err)
+	}
+	return run.ID
+}
+
+// testWord builds an entry with one meaning
+func testWord(word, partOfSpeech string, synonyms []string, definitions ...models.Definition) *models.Word {
+	return &models.Word{
+		Word:     word,
+		Meanings: []models.Meaning{{PartOfSpeech: partOfSpeech, Definitions: definitions, Synonyms: synonyms}},
+	}
+}
+
+// countRows returns the number of rows in table
+func countRows(tb testing.TB, db *sql.DB, table string) int {
+	tb.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+		tb.Fatal(err)
+	}
+	return n
+}
+
+// BenchmarkImport loads the Wordset letter file in test_data and imports it into a fresh
+// database, as an import run with default settings does
+func BenchmarkImport(b *testing.B) {
+	files := []string{filepath.Join("..", "..", "test_data", "j.json")}
+	words := 0
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		db, err := database.InitDB(filepath.Join(b.TempDir(), "words.db"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := tuneForImport(db); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		c, _, spill, err := loadCorpus(wordsetSource{}, files, 1, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if spill != nil {
+			spill.Close()
+		}
+		run, err := startRun(db, "wordset", files[0], modeSkip, nil, c.count)
+		if err != nil {
+			b.Fatal(err)
+		}
+		stream, err := c.open()
+		if err != nil {
+			b.Fatal(err)
+		}
+		opts := importOptions{Mode: modeSkip, Source: "wordset", BatchSize: 5000, FirstBatch: 1, RunID: run.ID}
+		if err := importToDatabase(context.Background(), db, stream, c.count, opts); err != nil {
+			b.Fatal(err)
+		}
+		words = c.count
+
+		b.StopTimer()
+		stream.Close()
+		db.Close()
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(words*b.N)/b.Elapsed().Seconds(), "words/s")
+}
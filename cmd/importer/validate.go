@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// Location identifies an entry within a source file. Line is 0 when the format has no useful
+// line numbers; Key is the headword or record ID when known.
+type Location struct {
+	File string
+	Line int
+	Key  string
+}
+
+// Issue kinds reported by validation
+const (
+	issueParseError          = "parse_error"
+	issueEmptyWord           = "empty_word"
+	issueNoDefinitions       = "no_definitions"
+	issueEmptyDefinition     = "empty_definition"
+	issueMalformedDefinition = "malformed_definition"
+	issueUnknownPartOfSpeech = "unknown_part_of_speech"
+)
+
+// Issue is a problem found in a source entry
+type Issue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	// Value is the offending value, e.g. the unrecognized part of speech
+	Value string `json:"value,omitempty"`
+}
+
+func newIssue(loc Location, kind, format string, args ...interface{}) Issue {
+	return Issue{File: loc.File, Line: loc.Line, Key: loc.Key, Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// knownPartsOfSpeech are the normalized names a source may produce without being flagged
+var knownPartsOfSpeech = map[string]bool{
+	"phrase": true, "abbreviation": true, "prefix": true, "suffix": true, "particle": true,
+	"symbol": true, "contraction": true, "letter": true,
+}
+
+func init() {
+	for _, name := range partsOfSpeech {
+		knownPartsOfSpeech[name] = true
+	}
+}
+
+// validateWord checks an entry before it is indexed. Empty definitions are dropped, along with
+// meanings left without definitions; everything else is kept and reported.
+func validateWord(loc Location, word *models.Word) []Issue {
+	var issues []Issue
+
+	if strings.TrimSpace(word.Word) == "" {
+		word.Meanings = nil
+		return append(issues, newIssue(loc, issueEmptyWord, "entry has no headword"))
+	}
+	if loc.Key == "" {
+		loc.Key = word.Word
+	}
+
+	meanings := word.Meanings[:0]
+	for _, m := range word.Meanings {
+		if !knownPartsOfSpeech[m.PartOfSpeech] {
+			issue := newIssue(loc, issueUnknownPartOfSpeech, "unknown part of speech %q", m.PartOfSpeech)
+			issue.Value = m.PartOfSpeech
+			issues = append(issues, issue)
+		}
+
+		definitions := m.Definitions[:0]
+		for _, d := range m.Definitions {
+			d.Definition = strings.TrimSpace(d.Definition)
+			if d.Definition == "" {
+				issues = append(issues, newIssue(loc, issueEmptyDefinition, "empty %s definition", m.PartOfSpeech))
+				continue
+			}
+			if reason := malformedReason(d.Definition); reason != "" {
+				issues = append(issues, newIssue(loc, issueMalformedDefinition, "%s: %q", reason, d.Definition))
+			}
+			definitions = append(definitions, d)
+		}
+
+		if len(definitions) > 0 {
+			m.Definitions = definitions
+			meanings = append(meanings, m)
+		}
+	}
+	word.Meanings = meanings
+
+	if len(meanings) == 0 {
+		issues = append(issues, newIssue(loc, issueNoDefinitions, "entry has no definitions"))
+	}
+	return issues
+}
+
+// malformedReason explains what looks wrong with a definition, or returns "" if nothing does
+func malformedReason(def string) string {
+	letters := false
+	depth := 0
+	for _, r := range def {
+		switch {
+		case unicode.IsLetter(r):
+			letters = true
+		case unicode.IsControl(r):
+			return "contains control characters"
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			depth--
+		}
+		if depth < 0 {
+			return "unbalanced brackets"
+		}
+	}
+
+	switch {
+	case !letters:
+		return "contains no letters"
+	case depth != 0:
+		return "unbalanced brackets"
+	case strings.Contains(def, "{{") || strings.Contains(def, "</"):
+		return "contains markup"
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// WiktionaryEntry represents one line of a Wiktionary extract (wiktextract/kaikki.org JSONL)
+type WiktionaryEntry struct {
+	Word     string            `json:"word"`
+	POS      string            `json:"pos"`
+	LangCode string            `json:"lang_code"`
+	Senses   []WiktionarySense `json:"senses"`
+	Sounds   []struct {
+		IPA    string `json:"ipa"`
+		MP3URL string `json:"mp3_url"`
+		OggURL string `json:"ogg_url"`
+	} `json:"sounds"`
+	Synonyms []WiktionaryLink `json:"synonyms"`
+	Antonyms []WiktionaryLink `json:"antonyms"`
+}
+
+type WiktionarySense struct {
+	Glosses  []string `json:"glosses"`
+	Examples []struct {
+		Text string `json:"text"`
+	} `json:"examples"`
+	Synonyms []WiktionaryLink `json:"synonyms"`
+	Antonyms []WiktionaryLink `json:"antonyms"`
+	Tags     []string         `json:"tags"`
+}
+
+type WiktionaryLink struct {
+	Word string `json:"word"`
+}
+
+// wiktionarySource reads English entries from a Wiktionary extract, one JSON object per line
+type wiktionarySource struct{}
+
+func (wiktionarySource) Name() string { return "wiktionary" }
+
+func (wiktionarySource) Files(path string) ([]string, error) {
+	return globFiles(path, "*.jsonl", "*.json")
+}
+
+func (wiktionarySource) Load(file string, index *WordIndex) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// Entries for common words can be several megabytes
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+
+	count := 0
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		loc := Location{File: file, Line: lineNum}
+		var entry WiktionaryEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			index.Problem(loc, issueParseError, "%v", err)
+			continue
+		}
+		if entry.LangCode != "" && entry.LangCode != "en" {
+			continue
+		}
+
+		loc.Key = entry.Word
+		index.Add(loc, convertWiktionaryToModel(entry))
+		count++
+	}
+
+	return count, scanner.Err()
+}
+
+// convertWiktionaryToModel converts an entry; senses without glosses are left out
+func convertWiktionaryToModel(entry WiktionaryEntry) *models.Word {
+	meaning := models.Meaning{
+		PartOfSpeech: normalizePartOfSpeech(entry.POS),
+		Synonyms:     linkWords(entry.Synonyms),
+		Antonyms:     linkWords(entry.Antonyms),
+	}
+
+	for _, sense := range entry.Senses {
+		if len(sense.Glosses) == 0 {
+			continue
+		}
+		def := models.Definition{
+			// Nested senses repeat the parent gloss first; the last one is the most specific
+			Definition: sense.Glosses[len(sense.Glosses)-1],
+			Synonyms:   linkWords(sense.Synonyms),
+			Antonyms:   linkWords(sense.Antonyms),
+		}
+		if len(sense.Examples) > 0 {
+			def.Example = sense.Examples[0].Text
+		}
+		meaning.Definitions = append(meaning.Definitions, def)
+	}
+
+	word := &models.Word{
+		Word:       strings.ToLower(entry.Word),
+		SourceUrls: []string{"https://en.wiktionary.org/wiki/" + strings.ReplaceAll(entry.Word, " ", "_")},
+		Meanings:   []models.Meaning{meaning},
+	}
+
+	for _, sound := range entry.Sounds {
+		audio := sound.MP3URL
+		if audio == "" {
+			audio = sound.OggURL
+		}
+		if sound.IPA == "" && audio == "" {
+			continue
+		}
+		if word.Phonetic == "" {
+			word.Phonetic = sound.IPA
+		}
+		word.Phonetics = append(word.Phonetics, models.Phonetic{Text: sound.IPA, Audio: audio})
+	}
+
+	return word
+}
+
+func linkWords(links []WiktionaryLink) []string {
+	var words []string
+	for _, l := range links {
+		if l.Word != "" {
+			words = append(words, l.Word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWiktionarySource(t *testing.T) {
+	runSourceTests(t, wiktionarySource{}, "kaikki.jsonl", []sourceTest{
+		{
+			name: "english entries",
+			content: `{"pos": "noun", "word": "dictionary", "lang": "English", "lang_code": "en", "sounds": [{"ipa": "/ˈdɪk.ʃə.nə.ɹi/", "tags": ["UK"]}, {"audio": "en-us-dictionary.ogg", "ogg_url": "https://upload.wikimedia.org/wikipedia/commons/8/8c/En-us-dictionary.ogg"}], "senses": [{"glosses": ["A reference work with a list of words from one or more languages, normally ordered alphabetically, explaining each word's meaning."], "examples": [{"text": "She looked up the word in the dictionary."}], "synonyms": [{"word": "wordbook"}]}, {"glosses": ["A reference work with a list of words from one or more languages, normally ordered alphabetically, explaining each word's meaning.", "Any work that has a list of material organized alphabetically."], "tags": ["broadly"]}], "synonyms": [{"word": "lexicon"}]}
+{"pos": "verb", "word": "dictionary", "lang": "English", "lang_code": "en", "senses": [{"glosses": ["To look up in a dictionary."], "tags": ["rare"]}]}
+`,
+			words: []string{
+				"dictionary|noun|A reference work with a list of words from one or more languages, normally ordered alphabetically, explaining each word's meaning.|She looked up the word in the dictionary.|wordbook/lexicon",
+				"dictionary|noun|Any work that has a list of material organized alphabetically.||/lexicon",
+				"dictionary|verb|To look up in a dictionary.||",
+			},
+			count: 2,
+		},
+		{
+			name:    "other languages",
+			content: `{"pos": "noun", "word": "dictionnaire", "lang": "French", "lang_code": "fr", "senses": [{"glosses": ["dictionary"]}]}` + "\n",
+			count:   0,
+		},
+		{
+			name: "malformed lines",
+			content: `{"pos": "noun", "word": "lexicon", "lang_code": "en", "senses": [{"glosses": ["The vocabulary used by a person or group."]}]
+
+{"pos": "noun", "word": "glossary", "lang_code": "en", "senses": [{"tags": ["no-gloss"]}]}
+{"pos": "noun", "word": "thesaurus", "lang_code": "en", "senses": [{"glosses": ["A book of synonyms."]}]}
+`,
+			words:  []string{"thesaurus|noun|A book of synonyms.||"},
+			issues: []string{issueParseError, issueNoDefinitions},
+			count:  2,
+		},
+	})
+}
+
+func TestConvertWiktionaryPhonetics(t *testing.T) {
+	var entry WiktionaryEntry
+	err := json.Unmarshal([]byte(`{"word": "Dictionary", "pos": "noun", "senses": [{"glosses": ["A reference work."]}], "sounds": [
+		{"ipa": "/ˈdɪk.ʃə.nə.ɹi/", "tags": ["UK"]},
+		{"rhymes": "-ɪkʃənəɹi"},
+		{"audio": "en-us-dictionary.ogg", "ogg_url": "https://example.org/dictionary.ogg", "mp3_url": "https://example.org/dictionary.mp3"}
+	]}`), &entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	word := convertWiktionaryToModel(entry)
+	if word.Word != "dictionary" || word.Phonetic != "/ˈdɪk.ʃə.nə.ɹi/" {
+		t.Errorf("word %q with phonetic %q", word.Word, word.Phonetic)
+	}
+	if len(word.Phonetics) != 2 || word.Phonetics[1].Audio != "https://example.org/dictionary.mp3" {
+		t.Errorf("phonetics %+v, want the IPA and the MP3 recording", word.Phonetics)
+	}
+}
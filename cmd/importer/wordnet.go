@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// wordnetSource reads the WordNet 3.x database files (data.noun, data.verb, data.adj, data.adv).
+// Each synset line becomes one definition for every lemma in the synset, with the other
+// lemmas as its synonyms.
+type wordnetSource struct{}
+
+func (wordnetSource) Name() string { return "wordnet" }
+
+func (wordnetSource) Files(path string) ([]string, error) {
+	return globFiles(path, "data.noun", "data.verb", "data.adj", "data.adv")
+}
+
+var (
+	wordnetExample = regexp.MustCompile(`"([^"]*)"`)
+	wordnetMarker  = regexp.MustCompile(`\((a|p|ip)\)$`)
+)
+
+func (wordnetSource) Load(file string, index *WordIndex) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	count := 0
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		// The license header lines start with spaces
+		if line == "" || line[0] == ' ' {
+			continue
+		}
+
+		loc := Location{File: file, Line: lineNum, Key: strings.SplitN(line, " ", 2)[0]}
+		lemmas, pos, gloss, err := parseWordnetLine(line)
+		if err != nil {
+			index.Problem(loc, issueParseError, "%v", err)
+			continue
+		}
+
+		definition, example := splitWordnetGloss(gloss)
+
+		for i, lemma := range lemmas {
+			var synonyms []string
+			for j, other := range lemmas {
+				if j != i {
+					synonyms = append(synonyms, other)
+				}
+			}
+
+			index.Add(loc, &models.Word{
+				Word:       strings.ToLower(lemma),
+				SourceUrls: []string{"https://wordnet.princeton.edu/"},
+				Meanings: []models.Meaning{{
+					PartOfSpeech: pos,
+					Definitions: []models.Definition{{
+						Definition: definition,
+						Example:    example,
+						Synonyms:   synonyms,
+					}},
+				}},
+			})
+			count++
+		}
+	}
+
+	return count, scanner.Err()
+}
+
+// parseWordnetLine extracts the lemmas, part of speech and gloss from a data file line:
+//
+//	synset_offset lex_filenum ss_type w_cnt word lex_id [word lex_id...] p_cnt [ptr...] [frames...] | gloss
+func parseWordnetLine(line string) ([]string, string, string, error) {
+	data, gloss, _ := strings.Cut(line, " | ")
+	fields := strings.Fields(data)
+	if len(fields) < 6 {
+		return nil, "", "", fmt.Errorf("malformed synset")
+	}
+
+	pos := normalizePartOfSpeech(fields[2])
+	wordCount, err := strconv.ParseInt(fields[3], 16, 32)
+	if err != nil || len(fields) < 4+int(wordCount)*2 {
+		return nil, "", "", fmt.Errorf("malformed word count %q", fields[3])
+	}
+
+	lemmas := make([]string, 0, wordCount)
+	for i := 0; i < int(wordCount); i++ {
+		lemma := fields[4+i*2]
+		lemma = wordnetMarker.ReplaceAllString(lemma, "")
+		lemmas = append(lemmas, strings.ReplaceAll(lemma, "_", " "))
+	}
+
+	return lemmas, pos, strings.TrimSpace(gloss), nil
+}
+
+// splitWordnetGloss separates a gloss into its definition and first quoted example
+func splitWordnetGloss(gloss string) (string, string) {
+	definition := gloss
+	if i := strings.Index(gloss, `"`); i >= 0 {
+		definition = gloss[:i]
+	}
+	definition = strings.TrimRight(strings.TrimSpace(definition), ";")
+
+	example := ""
+	if match := wordnetExample.FindStringSubmatch(gloss); match != nil {
+		example = strings.TrimSpace(match[1])
+	}
+
+	return strings.TrimSpace(definition), example
+}
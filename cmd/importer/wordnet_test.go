@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWordnetSource(t *testing.T) {
+	runSourceTests(t, wordnetSource{}, "data.adj", []sourceTest{
+		{
+			name: "synsets with pointers",
+			content: `  1 This software and database is being provided to you, the LICENSEE, by
+  2 Princeton University under the following license.
+00001740 00 a 01 able 0 005 = 05207437 n 0000 = 05624029 n 0000 + 05624029 n 0101 + 05207437 n 0101 ! 00002098 a 0101 | (usually followed by ` + "`to'" + `) having the necessary means or skill or know-how or authority to do something; "able to swim"; "she was able to program her computer"  
+00004980 00 s 02 abaxial 0 dorsal 4 002 & 00004413 a 0000 ;c 06037666 n 0000 | facing away from the axis of an organ or organism; "the abaxial surface of a leaf is the underside"  
+`,
+			words: []string{
+				"abaxial|adjective|facing away from the axis of an organ or organism|the abaxial surface of a leaf is the underside|dorsal",
+				"able|adjective|(usually followed by `to') having the necessary means or skill or know-how or authority to do something|able to swim|",
+				"dorsal|adjective|facing away from the axis of an organ or organism|the abaxial surface of a leaf is the underside|abaxial",
+			},
+			count: 3,
+		},
+		{
+			name:    "syntactic markers and collocations",
+			content: "00013160 00 s 02 galore(ip) 0 in_abundance 0 001 & 00013887 a 0000 | in great numbers\n",
+			words: []string{
+				"galore|adjective|in great numbers||in abundance",
+				"in abundance|adjective|in great numbers||galore",
+			},
+			count: 2,
+		},
+		{
+			name: "malformed lines",
+			content: `00001740 00 a
+00001740 00 a zz able 0 000 | having the necessary means
+00002098 00 a 01 unable 0 000 | (usually followed by ` + "`to'" + ` not having the necessary means  
+`,
+			words:  []string{"unable|adjective|(usually followed by `to' not having the necessary means||"},
+			issues: []string{issueParseError, issueParseError, issueMalformedDefinition},
+			count:  1,
+		},
+	})
+}
+
+func TestParseWordnetLine(t *testing.T) {
+	line := "00001740 03 n 01 entity 0 003 ~ 00001930 n 0000 ~ 00002137 n 0000 ~ 04431553 n 0000 | that which is perceived or known or inferred to have its own distinct existence (living or nonliving)  "
+	lemmas, pos, gloss, err := parseWordnetLine(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lemmas, []string{"entity"}) || pos != "noun" ||
+		gloss != "that which is perceived or known or inferred to have its own distinct existence (living or nonliving)" {
+		t.Errorf("parseWordnetLine = %q, %q, %q", lemmas, pos, gloss)
+	}
+
+	// A word count larger than the words present
+	if _, _, _, err := parseWordnetLine("00001740 03 n 03 entity 0 000 | gloss"); err == nil {
+		t.Error("parseWordnetLine accepted a synset with missing words")
+	}
+}
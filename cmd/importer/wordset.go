@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// WordsetEntry represents the Wordset JSON structure
+type WordsetEntry struct {
+	Word         string           `json:"word"`
+	WordsetID    string           `json:"wordset_id"`
+	Meanings     []WordsetMeaning `json:"meanings"`
+	Editors      []string         `json:"editors,omitempty"`
+	Contributors []string         `json:"contributors,omitempty"`
+}
+
+type WordsetMeaning struct {
+	ID         string   `json:"id"`
+	Definition string   `json:"def"`
+	Example    string   `json:"example,omitempty"`
+	SpeechPart string   `json:"speech_part"`
+	Synonyms   []string `json:"synonyms,omitempty"`
+}
+
+// wordsetSource reads the Wordset layout: one JSON object per letter, keyed by headword
+type wordsetSource struct{}
+
+func (wordsetSource) Name() string { return "wordset" }
+
+func (wordsetSource) Files(path string) ([]string, error) {
+	return globFiles(path, "*.json")
+}
+
+func (wordsetSource) Load(file string, index *WordIndex) (int, error) {
+	return loadWordsetFile(file, index)
+}
+
+func convertWordsetToModel(entry WordsetEntry) *models.Word {
+	word := &models.Word{
+		Word:       strings.ToLower(entry.Word),
+		Phonetic:   "", // Wordset doesn't include phonetics
+		SourceUrls: []string{wordsetDataURL(entry.Word)},
+		Meanings:   []models.Meaning{},
+		WordsetID:  entry.WordsetID,
+	}
+
+	for _, name := range entry.Editors {
+		word.Credits = append(word.Credits, models.Credit{Name: name, Role: models.CreditEditor, Source: "wordset"})
+	}
+	for _, name := range entry.Contributors {
+		word.Credits = append(word.Credits, models.Credit{Name: name, Role: models.CreditContributor, Source: "wordset"})
+	}
+
+	// Group meanings by part of speech
+	meaningsByPOS := make(map[string]*models.Meaning)
+
+	for _, wm := range entry.Meanings {
+		pos := wm.SpeechPart
+
+		// Get or create meaning for this POS
+		meaning, exists := meaningsByPOS[pos]
+		if !exists {
+			meaning = &models.Meaning{
+				PartOfSpeech: pos,
+				Definitions:  []models.Definition{},
+				Synonyms:     []string{},
+			}
+			meaningsByPOS[pos] = meaning
+		}
+
+		// Add definition
+		def := models.Definition{
+			Definition: wm.Definition,
+			Example:    wm.Example,
+			Synonyms:   wm.Synonyms,
+			ExternalID: wm.ID,
+		}
+		meaning.Definitions = append(meaning.Definitions, def)
+	}
+
+	// Convert map to slice
+	for _, meaning := range meaningsByPOS {
+		word.Meanings = append(word.Meanings, *meaning)
+	}
+
+	return word
+}
+
+// wordsetDataURL links to the upstream data file holding the entry. Wordset keeps one file
+// per initial letter and misc.json for everything else.
+func wordsetDataURL(word string) string {
+	name := "misc"
+	if word != "" {
+		if c := unicode.ToLower(rune(word[0])); c >= 'a' && c <= 'z' {
+			name = string(c)
+		}
+	}
+	return "https://github.com/wordset/wordset-dictionary/blob/master/data/" + name + ".json"
+}
+
+// loadWordsetFile decodes the file one entry at a time straight from disk, so memory use
+// doesn't grow with the file and a malformed entry is reported with its headword and line
+// while the rest of the file is still read
+func loadWordsetFile(filepath string, index *WordIndex) (int, error) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	lines := newLineCounter(f)
+	dec := json.NewDecoder(lines)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return 0, fmt.Errorf("expected a JSON object keyed by headword")
+	}
+
+	count := 0
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return count, fmt.Errorf("line %d: %w", lines.at(dec.InputOffset()), err)
+		}
+		key, _ := tok.(string)
+		loc := Location{File: filepath, Line: lines.at(dec.InputOffset()), Key: key}
+
+		var entry WordsetEntry
+		if err := dec.Decode(&entry); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return count, fmt.Errorf("line %d: %w", loc.Line, err)
+			}
+			// The decoder has consumed the whole value, so it can carry on with the next entry
+			index.Problem(loc, issueParseError, "%v", err)
+			continue
+		}
+
+		index.Add(loc, convertWordsetToModel(entry))
+		count++
+	}
+
+	return count, nil
+}
+
+// lineCounter converts increasing byte offsets into line numbers as a decoder reads through
+// it. It keeps only the bytes read ahead of the last offset asked for, so a file is never held
+// in memory.
+type lineCounter struct {
+	r io.Reader
+	// pending holds the bytes read from offset onwards that haven't been counted yet
+	pending []byte
+	offset  int64
+	line    int
+}
+
+func newLineCounter(r io.Reader) *lineCounter {
+	return &lineCounter{r: r, line: 1}
+}
+
+func (lc *lineCounter) Read(p []byte) (int, error) {
+	n, err := lc.r.Read(p)
+	lc.pending = append(lc.pending, p[:n]...)
+	return n, err
+}
+
+func (lc *lineCounter) at(offset int64) int {
+	if n := offset - lc.offset; n > 0 {
+		lc.line += bytes.Count(lc.pending[:n], []byte{'\n'})
+		lc.pending = append(lc.pending[:0], lc.pending[n:]...)
+		lc.offset = offset
+	}
+	return lc.line
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/words-api/words/internal/corpus"
+	"github.com/words-api/words/internal/database"
+)
+
+// maxNgram is the longest multi-word headword (in tokens) matched in sentences
+const maxNgram = 3
+
+// maxSentenceLength skips run-on "sentences" (tables, unpunctuated text) in characters
+const maxSentenceLength = 400
+
+// Miner finds headwords in corpus sentences and stores the good ones as candidate examples
+type Miner struct {
+	db         *sql.DB
+	headwords  map[string]int64 // tokenized headword -> word ID
+	counts     map[int64]int    // examples already stored per word
+	minScore   float64
+	maxPerWord int
+
+	tx      *sql.Tx
+	stmt    *sql.Stmt
+	pending int
+	added   []int64 // words given an example in the open transaction
+
+	sentences int
+	stored    int
+}
+
+func NewMiner(db *sql.DB, minScore float64, maxPerWord int) (*Miner, error) {
+	m := &Miner{
+		db:         db,
+		headwords:  make(map[string]int64),
+		counts:     make(map[int64]int),
+		minScore:   minScore,
+		maxPerWord: maxPerWord,
+	}
+
+	rows, err := db.Query(`SELECT id, word FROM words`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load headwords: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var word string
+		if err := rows.Scan(&id, &word); err != nil {
+			return nil, err
+		}
+		tokens := corpus.Tokenize(word)
+		if len(tokens) == 0 || len(tokens) > maxNgram {
+			continue
+		}
+		m.headwords[strings.Join(tokens, " ")] = id
+	}
+	rows.Close()
+
+	countRows, err := db.Query(`SELECT word_id, COUNT(*) FROM corpus_examples GROUP BY word_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count existing examples: %w", err)
+	}
+	defer countRows.Close()
+
+	for countRows.Next() {
+		var id int64
+		var count int
+		if err := countRows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		m.counts[id] = count
+	}
+
+	return m, nil
+}
+
+// MineFile reads a plain-text file paragraph by paragraph and stores matching sentences
+func (m *Miner) MineFile(path, source string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var paragraph []string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			paragraph = append(paragraph, line)
+			continue
+		}
+		if err := m.mineParagraph(strings.Join(paragraph, " "), source); err != nil {
+			return err
+		}
+		paragraph = paragraph[:0]
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return m.mineParagraph(strings.Join(paragraph, " "), source)
+}
+
+func (m *Miner) mineParagraph(text, source string) error {
+	if text == "" {
+		return nil
+	}
+
+	for _, sentence := range corpus.SplitSentences(text) {
+		if len(sentence) > maxSentenceLength {
+			continue
+		}
+		m.sentences++
+
+		tokens := corpus.Tokenize(sentence)
+		matched := make(map[int64]bool)
+		for i := range tokens {
+			for n := 1; n <= maxNgram && i+n <= len(tokens); n++ {
+				id, ok := m.headwords[strings.Join(tokens[i:i+n], " ")]
+				if !ok || matched[id] || m.counts[id] >= m.maxPerWord {
+					continue
+				}
+				matched[id] = true
+
+				// A multi-word headword at the start of the sentence still has context after it
+				score := corpus.Score(sentence, tokens, i+n-1)
+				if score < m.minScore {
+					continue
+				}
+				if err := m.store(id, sentence, source, i, len(tokens), score); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// store inserts a candidate example, batching inserts into transactions
+func (m *Miner) store(wordID int64, sentence, source string, position, length int, score float64) error {
+	if m.tx == nil {
+		tx, err := m.db.Begin()
+		if err != nil {
+			return err
+		}
+		stmt, err := tx.Prepare(`
+			INSERT OR IGNORE INTO corpus_examples (word_id, sentence, source, position, length, score, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		m.tx, m.stmt = tx, stmt
+	}
+
+	result, err := m.stmt.Exec(wordID, sentence, source, position, length, score, time.Now())
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		m.counts[wordID]++
+		m.stored++
+		m.added = append(m.added, wordID)
+	}
+
+	m.pending++
+	if m.pending >= 1000 {
+		return m.Flush()
+	}
+	return nil
+}
+
+// Flush commits any pending inserts
+func (m *Miner) Flush() error {
+	if m.tx == nil {
+		return nil
+	}
+	m.stmt.Close()
+	err := m.tx.Commit()
+	m.tx, m.stmt, m.pending, m.added = nil, nil, 0, m.added[:0]
+	return err
+}
+
+// Rollback discards any pending inserts and takes them back out of the counts
+func (m *Miner) Rollback() error {
+	if m.tx == nil {
+		return nil
+	}
+	m.stmt.Close()
+	err := m.tx.Rollback()
+	for _, id := range m.added {
+		m.counts[id]--
+	}
+	m.stored -= len(m.added)
+	m.tx, m.stmt, m.pending, m.added = nil, nil, 0, m.added[:0]
+	return err
+}
+
+// collectFiles expands directories into the .txt files they contain
+func collectFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".txt") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func main() {
+	dbPath := flag.String("db", "words.db", "Path to the SQLite database")
+	source := flag.String("source", "", "Source label stored with each example (default: file name)")
+	minScore := flag.Float64("min-score", 0.5, "Minimum quality score (0-1) for a sentence to be stored")
+	maxPerWord := flag.Int("max-per-word", 50, "Maximum number of examples stored per headword")
+	flag.Usage = func() {
+		fmt.Println("Usage: miner [flags] <corpus-file-or-dir>...")
+		fmt.Println("Example: miner -source gutenberg corpora/gutenberg/")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	db, err := database.InitDB(*dbPath)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	files, err := collectFiles(flag.Args())
+	if err != nil {
+		log.Fatalf("Failed to list corpus files: %v", err)
+	}
+
+	fmt.Println("⛏️  Mining example sentences...")
+	startTime := time.Now()
+
+	miner, err := NewMiner(db, *minScore, *maxPerWord)
+	if err != nil {
+		log.Fatalf("Failed to prepare miner: %v", err)
+	}
+	fmt.Printf("📚 %d headwords loaded\n", len(miner.headwords))
+
+	for i, file := range files {
+		label := *source
+		if label == "" {
+			label = filepath.Base(file)
+		}
+
+		before := miner.stored
+		if err := miner.MineFile(file, label); err != nil {
+			log.Printf("Error mining %s: %v", file, err)
+			// Drop the file's uncommitted examples so the next file starts a fresh transaction
+			if err := miner.Rollback(); err != nil {
+				log.Fatalf("Failed to roll back examples: %v", err)
+			}
+			continue
+		}
+		if err := miner.Flush(); err != nil {
+			log.Fatalf("Failed to commit examples: %v", err)
+		}
+		fmt.Printf("  [%d/%d] %s: %d examples\n", i+1, len(files), filepath.Base(file), miner.stored-before)
+	}
+
+	if err := miner.Flush(); err != nil {
+		log.Fatalf("Failed to commit examples: %v", err)
+	}
+
+	fmt.Printf("\n✅ Scanned %d sentences, stored %d examples in %s\n",
+		miner.sentences, miner.stored, time.Since(startTime).Round(time.Millisecond))
+}
@@ -1,7 +1,9 @@
 package auth
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/words-api/words/internal/models"
@@ -49,3 +51,21 @@ func GetAuthenticatedUser(c *gin.Context) (*models.User, bool) {
 	}
 	return user.(*models.User), true
 }
+
+// AdminMiddleware restricts a route group to moderators: signed-in users who also send the
+// configured admin token as "Authorization: Bearer <token>". Logins need no password, so the
+// session only says who is moderating. With an empty token the routes are closed to everyone.
+// It must run after AuthMiddleware.
+func AdminMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "admin access required",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		token, header string
+		want          int
+	}{
+		{"matching token", "s3cret", "Bearer s3cret", http.StatusOK},
+		{"wrong token", "s3cret", "Bearer guess", http.StatusForbidden},
+		{"no header", "s3cret", "", http.StatusForbidden},
+		{"not a bearer token", "s3cret", "s3cret", http.StatusForbidden},
+		{"no token configured", "", "Bearer ", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin", AdminMiddleware(tt.token), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			request := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				request.Header.Set("Authorization", tt.header)
+			}
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			if response.Code != tt.want {
+				t.Errorf("status %d, want %d", response.Code, tt.want)
+			}
+		})
+	}
+}
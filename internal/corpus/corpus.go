@@ -0,0 +1,151 @@
+// Package corpus splits plain text into sentences and scores them as dictionary examples
+package corpus
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Ideal example length in tokens; sentences outside this range are penalized
+const (
+	MinIdealLength = 6
+	MaxIdealLength = 25
+)
+
+// abbreviations that end in a period without ending the sentence
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true, "jr": true,
+	"st": true, "vs": true, "etc": true, "e.g": true, "i.e": true, "no": true, "vol": true,
+	"fig": true, "mt": true, "capt": true, "col": true, "gen": true, "lt": true, "rev": true,
+}
+
+// SplitSentences breaks a paragraph into sentences on terminal punctuation, keeping
+// abbreviations and initials intact. Line breaks inside the paragraph are treated as spaces.
+func SplitSentences(text string) []string {
+	words := strings.Fields(text)
+	var sentences []string
+	var current []string
+
+	for i, w := range words {
+		current = append(current, w)
+		if !endsSentence(w) {
+			continue
+		}
+		// A sentence only ends before a capitalized word or an opening quote
+		if i+1 < len(words) {
+			next := []rune(strings.TrimLeft(words[i+1], `"'“‘(`))
+			if len(next) == 0 || !unicode.IsUpper(next[0]) {
+				continue
+			}
+		}
+		sentences = append(sentences, strings.Join(current, " "))
+		current = nil
+	}
+
+	if len(current) > 0 {
+		sentences = append(sentences, strings.Join(current, " "))
+	}
+
+	return sentences
+}
+
+// endsSentence reports whether a token closes a sentence
+func endsSentence(token string) bool {
+	trimmed := strings.TrimRight(token, `"'”’)`)
+	if trimmed == "" {
+		return false
+	}
+
+	switch trimmed[len(trimmed)-1] {
+	case '!', '?':
+		return true
+	case '.':
+		base := strings.ToLower(strings.TrimSuffix(strings.TrimLeft(trimmed, `"'“‘(`), "."))
+		// Initials like "J." and known abbreviations don't end a sentence
+		if len([]rune(base)) == 1 || abbreviations[base] {
+			return false
+		}
+		return true
+	}
+
+	return false
+}
+
+// Tokenize lowercases a sentence and splits it into word tokens, dropping punctuation.
+// Internal apostrophes and hyphens are kept ("don't", "well-known").
+func Tokenize(sentence string) []string {
+	var tokens []string
+	for _, field := range strings.Fields(sentence) {
+		token := strings.TrimFunc(strings.ToLower(field), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// Score rates how suitable a sentence is as an example for the headword found at position.
+// Good examples are complete, mid-length sentences that use the word mid-sentence
+// and are made of ordinary words. The result is in the range [0, 1].
+func Score(sentence string, tokens []string, position int) float64 {
+	score := 1.0
+
+	// Length: penalize very short and very long sentences
+	n := len(tokens)
+	switch {
+	case n < 4:
+		return 0
+	case n < MinIdealLength:
+		score *= 0.6
+	case n > MaxIdealLength*2:
+		score *= 0.3
+	case n > MaxIdealLength:
+		score *= 0.7
+	}
+
+	// Position: a headword in the middle has context on both sides
+	if position == 0 || position == n-1 {
+		score *= 0.7
+	}
+
+	// Form: starts with a capital letter and ends with terminal punctuation
+	runes := []rune(strings.TrimLeft(sentence, `"'“‘(`))
+	if len(runes) == 0 || !unicode.IsUpper(runes[0]) {
+		score *= 0.7
+	}
+	if !endsSentence(strings.TrimSpace(sentence)) {
+		score *= 0.8
+	}
+
+	// Noise: digits, shouting and markup suggest tables, headings or code
+	var digits, upper, letters, symbols int
+	for _, r := range sentence {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsUpper(r):
+			upper++
+			letters++
+		case unicode.IsLetter(r):
+			letters++
+		case strings.ContainsRune("<>{}[]|_*#=@/\\", r):
+			symbols++
+		}
+	}
+	if letters == 0 {
+		return 0
+	}
+	if digits > 0 {
+		score *= 0.85
+	}
+	if float64(upper)/float64(letters) > 0.3 {
+		score *= 0.4
+	}
+	if symbols > 0 {
+		score *= 0.5
+	}
+
+	return score
+}
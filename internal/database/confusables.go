@@ -0,0 +1,78 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// confusables is the curated list of word pairs learners commonly mix up.
+// Pairs are normalized (lowercased, ordered) before being stored.
+var confusables = []struct {
+	a, b, note string
+}{
+	{"affect", "effect", "affect is usually the verb (to influence); effect is usually the noun (a result)"},
+	{"accept", "except", "accept means to receive; except means excluding"},
+	{"adverse", "averse", "adverse means harmful; averse means opposed to"},
+	{"advice", "advise", "advice is the noun; advise is the verb"},
+	{"allude", "elude", "allude means to refer indirectly; elude means to escape"},
+	{"allusion", "illusion", "an allusion is an indirect reference; an illusion is a false perception"},
+	{"appraise", "apprise", "appraise means to assess value; apprise means to inform"},
+	{"assure", "ensure", "assure means to tell confidently; ensure means to make certain"},
+	{"ensure", "insure", "ensure means to make certain; insure means to protect financially"},
+	{"breath", "breathe", "breath is the noun; breathe is the verb"},
+	{"capital", "capitol", "a capitol is a legislative building; capital covers most other senses"},
+	{"cite", "site", "cite means to quote; a site is a location"},
+	{"complement", "compliment", "a complement completes something; a compliment is praise"},
+	{"complementary", "complimentary", "complementary means completing; complimentary means praising or free"},
+	{"council", "counsel", "a council is a group; counsel is advice or a lawyer"},
+	{"continual", "continuous", "continual means recurring; continuous means without interruption"},
+	{"desert", "dessert", "a desert is arid land; a dessert is a sweet course"},
+	{"discreet", "discrete", "discreet means careful or tactful; discrete means separate"},
+	{"disinterested", "uninterested", "disinterested means impartial; uninterested means not interested"},
+	{"elicit", "illicit", "elicit means to draw out; illicit means unlawful"},
+	{"emigrate", "immigrate", "emigrate is to leave a country; immigrate is to enter one"},
+	{"eminent", "imminent", "eminent means distinguished; imminent means about to happen"},
+	{"farther", "further", "farther refers to physical distance; further to degree or extent"},
+	{"flaunt", "flout", "flaunt means to show off; flout means to disregard a rule"},
+	{"flair", "flare", "flair is a natural talent; a flare is a sudden blaze of light"},
+	{"imply", "infer", "the speaker implies; the listener infers"},
+	{"lay", "lie", "lay takes an object (lay the book down); lie does not (lie down)"},
+	{"loose", "lose", "loose means not tight; lose means to misplace or fail to win"},
+	{"militate", "mitigate", "militate means to have force against; mitigate means to lessen"},
+	{"principal", "principle", "a principal is a head or main sum; a principle is a rule or belief"},
+	{"precede", "proceed", "precede means to come before; proceed means to go on"},
+	{"prescribe", "proscribe", "prescribe means to recommend; proscribe means to forbid"},
+	{"stationary", "stationery", "stationary means not moving; stationery is writing material"},
+	{"tortuous", "torturous", "tortuous means twisting; torturous means painful"},
+	{"venal", "venial", "venal means corruptible; venial means forgivable"},
+	{"wreak", "reek", "wreak means to inflict; reek means to smell strongly"},
+}
+
+// seedConfusables inserts the curated confusable pairs, leaving existing rows untouched
+func seedConfusables(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO confusable_pairs (word_a, word_b, note) VALUES (?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range confusables {
+		a, b := strings.ToLower(c.a), strings.ToLower(c.b)
+		if a > b {
+			a, b = b, a
+		}
+		if _, err := stmt.Exec(a, b, c.note); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
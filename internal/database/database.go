@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -24,15 +25,56 @@ func InitDB(filepath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to create tables: %w", err)
 	}
 
+	// Bring databases created by earlier versions up to date
+	if err := migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	if err := CreateDictionaryIndexes(db); err != nil {
+		return nil, fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	// Load curated reference data
+	if err := seedConfusables(db); err != nil {
+		return nil, fmt.Errorf("failed to seed confusable pairs: %w", err)
+	}
+
 	return db, nil
 }
 
+// userWordsTable is the schema of user_words, which holds one row per card: each card type a
+// user studies a word with is scheduled on its own
+const userWordsTable = `
+	CREATE TABLE IF NOT EXISTS %s (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		word_id INTEGER NOT NULL,
+		card_type TEXT NOT NULL DEFAULT 'recognition',
+		added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		status TEXT NOT NULL DEFAULT 'learning',
+		next_review_date DATETIME NOT NULL,
+		ease_factor REAL NOT NULL DEFAULT 2.5,
+		interval_days INTEGER NOT NULL DEFAULT 1,
+		leitner_box INTEGER NOT NULL DEFAULT 1,
+		fsrs_stability REAL NOT NULL DEFAULT 0,
+		fsrs_difficulty REAL NOT NULL DEFAULT 0,
+		last_reviewed_at DATETIME,
+		learning_step INTEGER NOT NULL DEFAULT 0,
+		relearning INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+		UNIQUE(user_id, word_id, card_type)
+	);
+`
+
 func createTables(db *sql.DB) error {
 	schema := `
 	CREATE TABLE IF NOT EXISTS words (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		word TEXT NOT NULL UNIQUE,
 		phonetic TEXT,
+		wordset_id TEXT,
+		import_run_id INTEGER,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
@@ -44,6 +86,8 @@ func createTables(db *sql.DB) error {
 		word_id INTEGER NOT NULL,
 		text TEXT NOT NULL,
 		audio TEXT,
+		source TEXT,
+		import_run_id INTEGER,
 		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
 	);
 
@@ -51,6 +95,8 @@ func createTables(db *sql.DB) error {
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		word_id INTEGER NOT NULL,
 		part_of_speech TEXT NOT NULL,
+		source TEXT,
+		import_run_id INTEGER,
 		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
 	);
 
@@ -59,6 +105,9 @@ func createTables(db *sql.DB) error {
 		meaning_id INTEGER NOT NULL,
 		definition TEXT NOT NULL,
 		example TEXT,
+		source TEXT,
+		external_id TEXT,
+		import_run_id INTEGER,
 		FOREIGN KEY (meaning_id) REFERENCES meanings(id) ON DELETE CASCADE
 	);
 
@@ -67,6 +116,8 @@ func createTables(db *sql.DB) error {
 		meaning_id INTEGER,
 		definition_id INTEGER,
 		synonym TEXT NOT NULL,
+		source TEXT,
+		import_run_id INTEGER,
 		CHECK ((meaning_id IS NOT NULL) OR (definition_id IS NOT NULL)),
 		FOREIGN KEY (meaning_id) REFERENCES meanings(id) ON DELETE CASCADE,
 		FOREIGN KEY (definition_id) REFERENCES definitions(id) ON DELETE CASCADE
@@ -77,6 +128,8 @@ func createTables(db *sql.DB) error {
 		meaning_id INTEGER,
 		definition_id INTEGER,
 		antonym TEXT NOT NULL,
+		source TEXT,
+		import_run_id INTEGER,
 		CHECK ((meaning_id IS NOT NULL) OR (definition_id IS NOT NULL)),
 		FOREIGN KEY (meaning_id) REFERENCES meanings(id) ON DELETE CASCADE,
 		FOREIGN KEY (definition_id) REFERENCES definitions(id) ON DELETE CASCADE
@@ -86,32 +139,74 @@ func createTables(db *sql.DB) error {
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		word_id INTEGER NOT NULL,
 		url TEXT NOT NULL,
+		source TEXT,
+		import_run_id INTEGER,
 		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
 	);
 
+	-- People credited for an entry by an upstream dataset (Wordset editors and contributors)
+
+	CREATE TABLE IF NOT EXISTS word_credits (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		word_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		role TEXT NOT NULL,
+		source TEXT NOT NULL,
+		import_run_id INTEGER,
+		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+		UNIQUE(word_id, name, role, source)
+	);
+
+	-- Bulk import runs and the batches each has committed, so an import can resume or be rolled back
+
+	CREATE TABLE IF NOT EXISTS import_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		source TEXT NOT NULL,
+		path TEXT NOT NULL,
+		mode TEXT NOT NULL,
+		files TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'running',
+		total_words INTEGER NOT NULL DEFAULT 0,
+		imported_words INTEGER NOT NULL DEFAULT 0,
+		failed_words INTEGER NOT NULL DEFAULT 0,
+		last_word TEXT,
+		error TEXT,
+		started_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		finished_at DATETIME
+	);
+
+	CREATE TABLE IF NOT EXISTS import_checkpoints (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		run_id INTEGER NOT NULL,
+		batch INTEGER NOT NULL,
+		first_word TEXT NOT NULL,
+		last_word TEXT NOT NULL,
+		imported INTEGER NOT NULL,
+		failed INTEGER NOT NULL,
+		committed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (run_id) REFERENCES import_runs(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_import_checkpoints_run ON import_checkpoints(run_id, batch);
+
 	-- Phase 2: User Management and Spaced Repetition
 
 	CREATE TABLE IF NOT EXISTS users (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		username TEXT NOT NULL UNIQUE,
+		scheduler TEXT NOT NULL DEFAULT 'sm2',
+		fsrs_weights TEXT,
+		learning_steps TEXT NOT NULL DEFAULT '1m 10m',
+		relearning_steps TEXT NOT NULL DEFAULT '10m',
+		new_per_day INTEGER NOT NULL DEFAULT 20,
+		reviews_per_day INTEGER NOT NULL DEFAULT 200,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
 
-	CREATE TABLE IF NOT EXISTS user_words (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		user_id INTEGER NOT NULL,
-		word_id INTEGER NOT NULL,
-		added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		status TEXT NOT NULL DEFAULT 'learning',
-		next_review_date DATETIME NOT NULL,
-		ease_factor REAL NOT NULL DEFAULT 2.5,
-		interval_days INTEGER NOT NULL DEFAULT 1,
-		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
-		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
-		UNIQUE(user_id, word_id)
-	);
+	` + fmt.Sprintf(userWordsTable, "user_words") + `
 
 	CREATE INDEX IF NOT EXISTS idx_user_words_user_id ON user_words(user_id);
 	CREATE INDEX IF NOT EXISTS idx_user_words_next_review ON user_words(user_id, next_review_date);
@@ -124,13 +219,303 @@ func createTables(db *sql.DB) error {
 		quality INTEGER NOT NULL CHECK(quality >= 0 AND quality <= 5),
 		interval_days INTEGER NOT NULL,
 		ease_factor REAL NOT NULL,
+		prior_state TEXT,
+		card_type TEXT NOT NULL DEFAULT 'recognition',
 		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
 		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_review_history_user_word ON review_history(user_id, word_id);
+
+	CREATE TABLE IF NOT EXISTS review_sessions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		started_at DATETIME NOT NULL,
+		ended_at DATETIME,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_review_sessions_user ON review_sessions(user_id, started_at);
+
+	CREATE TABLE IF NOT EXISTS review_session_cards (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		word_id INTEGER NOT NULL,
+		card_type TEXT NOT NULL DEFAULT 'recognition',
+		quality INTEGER,
+		answered_at DATETIME,
+		review_id INTEGER,
+		FOREIGN KEY (session_id) REFERENCES review_sessions(id) ON DELETE CASCADE,
+		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+		UNIQUE(session_id, position)
+	);
+
+	CREATE TABLE IF NOT EXISTS user_word_notes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		word_id INTEGER NOT NULL,
+		notes TEXT,
+		mnemonic TEXT,
+		preferred_definition_id INTEGER,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+		FOREIGN KEY (preferred_definition_id) REFERENCES definitions(id) ON DELETE SET NULL,
+		UNIQUE(user_id, word_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS user_word_examples (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		word_id INTEGER NOT NULL,
+		sentence TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_user_word_examples_user_word ON user_word_examples(user_id, word_id);
+
+	-- Curated pairs of commonly confused words (stored with word_a < word_b)
+
+	CREATE TABLE IF NOT EXISTS confusable_pairs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		word_a TEXT NOT NULL,
+		word_b TEXT NOT NULL,
+		note TEXT,
+		CHECK (word_a < word_b),
+		UNIQUE(word_a, word_b)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_confusable_pairs_word_b ON confusable_pairs(word_b);
+
+	-- Candidate example sentences mined from local text corpora
+
+	CREATE TABLE IF NOT EXISTS corpus_examples (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		word_id INTEGER NOT NULL,
+		sentence TEXT NOT NULL,
+		source TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		length INTEGER NOT NULL,
+		score REAL NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+		UNIQUE(word_id, sentence)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_corpus_examples_word_score ON corpus_examples(word_id, score DESC);
+
+	-- User-contributed dictionary changes awaiting moderation
+
+	CREATE TABLE IF NOT EXISTS pending_changes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		word_id INTEGER NOT NULL,
+		change_type TEXT NOT NULL,
+		definition_id INTEGER,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		reviewed_by INTEGER,
+		reviewed_at DATETIME,
+		review_note TEXT,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+		FOREIGN KEY (definition_id) REFERENCES definitions(id) ON DELETE SET NULL,
+		FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_pending_changes_status ON pending_changes(status, created_at);
+	CREATE INDEX IF NOT EXISTS idx_pending_changes_user ON pending_changes(user_id);
+
+	CREATE TABLE IF NOT EXISTS change_audit (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		change_id INTEGER NOT NULL,
+		actor_id INTEGER NOT NULL,
+		action TEXT NOT NULL,
+		table_name TEXT,
+		row_id INTEGER,
+		old_value TEXT,
+		new_value TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (change_id) REFERENCES pending_changes(id) ON DELETE CASCADE,
+		FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_change_audit_change ON change_audit(change_id);
 	`
 
 	_, err := db.Exec(schema)
 	return err
 }
+
+// dictionaryIndexes cover the foreign keys used to assemble a word. They are kept out of
+// createTables so bulk imports can drop them and rebuild them once at the end.
+var dictionaryIndexes = []struct {
+	name, definition string
+}{
+	{"idx_phonetics_word_id", "phonetics(word_id)"},
+	{"idx_meanings_word_id", "meanings(word_id)"},
+	{"idx_definitions_meaning_id", "definitions(meaning_id)"},
+	{"idx_synonyms_meaning_id", "synonyms(meaning_id)"},
+	{"idx_synonyms_definition_id", "synonyms(definition_id)"},
+	{"idx_antonyms_meaning_id", "antonyms(meaning_id)"},
+	{"idx_antonyms_definition_id", "antonyms(definition_id)"},
+	{"idx_source_urls_word_id", "source_urls(word_id)"},
+}
+
+// CreateDictionaryIndexes creates the dictionary foreign key indexes if they don't exist
+func CreateDictionaryIndexes(db *sql.DB) error {
+	for _, idx := range dictionaryIndexes {
+		if _, err := db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s", idx.name, idx.definition)); err != nil {
+			return fmt.Errorf("failed to create %s: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+// DropDictionaryIndexes drops the dictionary foreign key indexes ahead of a bulk load
+func DropDictionaryIndexes(db *sql.DB) error {
+	for _, idx := range dictionaryIndexes {
+		if _, err := db.Exec(fmt.Sprintf("DROP INDEX IF EXISTS %s", idx.name)); err != nil {
+			return fmt.Errorf("failed to drop %s: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+// migrate adds columns introduced after a table was first created
+func migrate(db *sql.DB) error {
+	columns := []struct {
+		table, column, definition string
+	}{
+		// Which dataset contributed each dictionary row, so re-imports can replace their own rows
+		{"phonetics", "source", "TEXT"},
+		{"meanings", "source", "TEXT"},
+		{"definitions", "source", "TEXT"},
+		{"synonyms", "source", "TEXT"},
+		{"antonyms", "source", "TEXT"},
+		{"source_urls", "source", "TEXT"},
+		// Stable upstream IDs, so re-imports can match entries and senses exactly
+		{"words", "wordset_id", "TEXT"},
+		{"definitions", "external_id", "TEXT"},
+		// The import run that inserted each dictionary row, so a failed run can be rolled back
+		{"words", "import_run_id", "INTEGER"},
+		{"phonetics", "import_run_id", "INTEGER"},
+		{"meanings", "import_run_id", "INTEGER"},
+		{"definitions", "import_run_id", "INTEGER"},
+		{"synonyms", "import_run_id", "INTEGER"},
+		{"antonyms", "import_run_id", "INTEGER"},
+		{"source_urls", "import_run_id", "INTEGER"},
+		{"word_credits", "import_run_id", "INTEGER"},
+		// Each user's review scheduling algorithm, and the state the non-SM-2 ones keep per word
+		{"users", "scheduler", "TEXT NOT NULL DEFAULT 'sm2'"},
+		{"user_words", "leitner_box", "INTEGER NOT NULL DEFAULT 1"},
+		// FSRS memory state per word, and weights fitted to each user's review history
+		{"user_words", "fsrs_stability", "REAL NOT NULL DEFAULT 0"},
+		{"user_words", "fsrs_difficulty", "REAL NOT NULL DEFAULT 0"},
+		{"user_words", "last_reviewed_at", "DATETIME"},
+		{"users", "fsrs_weights", "TEXT"},
+		// Sub-day learning steps per user, and where each word is in them
+		{"users", "learning_steps", "TEXT NOT NULL DEFAULT '1m 10m'"},
+		{"users", "relearning_steps", "TEXT NOT NULL DEFAULT '10m'"},
+		{"user_words", "learning_step", "INTEGER NOT NULL DEFAULT 0"},
+		{"user_words", "relearning", "INTEGER NOT NULL DEFAULT 0"},
+		// Daily new word and review limits per user
+		{"users", "new_per_day", "INTEGER NOT NULL DEFAULT 20"},
+		{"users", "reviews_per_day", "INTEGER NOT NULL DEFAULT 200"},
+		// The word's scheduling state before each review, so the review can be undone
+		{"review_history", "prior_state", "TEXT"},
+		// The card type each review and queued session card is for
+		{"review_history", "card_type", "TEXT NOT NULL DEFAULT 'recognition'"},
+		{"review_session_cards", "card_type", "TEXT NOT NULL DEFAULT 'recognition'"},
+	}
+
+	for _, c := range columns {
+		if err := addColumnIfMissing(db, c.table, c.column, c.definition); err != nil {
+			return fmt.Errorf("failed to add %s.%s: %w", c.table, c.column, err)
+		}
+	}
+
+	if err := migrateCardTypes(db); err != nil {
+		return fmt.Errorf("failed to add card types: %w", err)
+	}
+
+	return nil
+}
+
+// migrateCardTypes turns user_words from one row per word into one row per card. Its unique
+// constraint can't be changed in place, so the table is rebuilt; existing rows become
+// recognition cards.
+func migrateCardTypes(db *sql.DB) error {
+	columns, err := tableColumns(db, "user_words")
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if column == "card_type" {
+			return nil
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	list := strings.Join(columns, ", ")
+	statements := []string{
+		fmt.Sprintf(userWordsTable, "user_words_cards"),
+		fmt.Sprintf("INSERT INTO user_words_cards (%s) SELECT %s FROM user_words", list, list),
+		"DROP TABLE user_words",
+		"ALTER TABLE user_words_cards RENAME TO user_words",
+		"CREATE INDEX IF NOT EXISTS idx_user_words_user_id ON user_words(user_id)",
+		"CREATE INDEX IF NOT EXISTS idx_user_words_next_review ON user_words(user_id, next_review_date)",
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// tableColumns returns the names of a table's columns
+func tableColumns(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
+// addColumnIfMissing adds a column to a table unless it already exists
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	columns, err := tableColumns(db, table)
+	if err != nil {
+		return err
+	}
+	for _, name := range columns {
+		if name == column {
+			return nil
+		}
+	}
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// wordCacheControl lets browsers and the nginx front cache dictionary entries
+// for an hour, then revalidate them with a conditional GET
+const wordCacheControl = "public, max-age=3600, stale-while-revalidate=86400"
+
+// entityTag builds a weak ETag from a row ID, its last update time and the media type
+// of the representation. It is weak because nginx may re-encode the body (gzip).
+func entityTag(id int64, updatedAt time.Time, mediaType string) string {
+	variant := strings.NewReplacer("/", "-", "+", "-").Replace(mediaType)
+	return fmt.Sprintf(`W/"%d-%x-%s"`, id, updatedAt.UnixNano(), variant)
+}
+
+// setCacheHeaders sets the validators and caching policy for a response
+func setCacheHeaders(c *gin.Context, etag string, lastModified time.Time) {
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", wordCacheControl)
+}
+
+// notModified reports whether the request's conditional headers match the current representation.
+// If-None-Match takes precedence over If-Modified-Since (RFC 9110, section 13.2.2).
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		// HTTP dates have one-second resolution
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// etagMatches performs the weak comparison of an If-None-Match header against an ETag
+func etagMatches(header, etag string) bool {
+	current := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == current {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/words-api/words/internal/services"
+)
+
+// CompareHandler handles HTTP requests for comparing confusable words
+type CompareHandler struct {
+	service *services.CompareService
+}
+
+// NewCompareHandler creates a new compare handler
+func NewCompareHandler(db *sql.DB) *CompareHandler {
+	return &CompareHandler{
+		service: services.NewCompareService(db),
+	}
+}
+
+// CompareWords handles GET /api/compare?a=&b=
+func (h *CompareHandler) CompareWords(c *gin.Context) {
+	a := c.Query("a")
+	b := c.Query("b")
+
+	comparison, err := h.service.Compare(a, b)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "both words are required" || err.Error() == "words must be different" {
+			statusCode = http.StatusBadRequest
+		} else if strings.Contains(err.Error(), "word not found") {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
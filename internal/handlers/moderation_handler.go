@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/words-api/words/internal/auth"
+	"github.com/words-api/words/internal/models"
+	"github.com/words-api/words/internal/services"
+)
+
+// ModerationHandler handles HTTP requests for user-contributed changes and their moderation
+type ModerationHandler struct {
+	service *services.ModerationService
+}
+
+// NewModerationHandler creates a new moderation handler
+func NewModerationHandler(db *sql.DB) *ModerationHandler {
+	return &ModerationHandler{
+		service: services.NewModerationService(db),
+	}
+}
+
+// ProposeChange handles POST /api/words/:word/changes (authenticated endpoint)
+func (h *ModerationHandler) ProposeChange(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	word := c.Param("word")
+
+	var request models.ProposeChangeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "change_type is required",
+		})
+		return
+	}
+
+	change, err := h.service.ProposeChange(user.Username, word, request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case err.Error() == "user not found" || strings.HasPrefix(err.Error(), "word not found"):
+			statusCode = http.StatusNotFound
+		case err.Error() == "invalid change type" ||
+			err.Error() == "definition not found" ||
+			err.Error() == "part of speech is required" ||
+			err.Error() == "definition is required" ||
+			err.Error() == "definition or example is required" ||
+			err.Error() == "example is required" ||
+			err.Error() == "at least one synonym is required":
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, change)
+}
+
+// GetUserChanges handles GET /api/user/changes (authenticated endpoint)
+func (h *ModerationHandler) GetUserChanges(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	changes, err := h.service.GetUserChanges(user.Username)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "user not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "failed to retrieve changes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changes": changes,
+		"count":   len(changes),
+	})
+}
+
+// ListChanges handles GET /api/admin/changes (admin endpoint)
+func (h *ModerationHandler) ListChanges(c *gin.Context) {
+	status := c.DefaultQuery("status", "pending") // pending, approved, rejected, or empty for all
+
+	changes, err := h.service.ListChanges(status)
+	if err != nil {
+		if err.Error() == "invalid status" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "status must be pending, approved or rejected",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "failed to retrieve changes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changes": changes,
+		"count":   len(changes),
+	})
+}
+
+// GetChange handles GET /api/admin/changes/:id (admin endpoint)
+func (h *ModerationHandler) GetChange(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid change ID",
+		})
+		return
+	}
+
+	change, err := h.service.GetChange(id)
+	if err != nil {
+		if err.Error() == "change not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "change not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "failed to retrieve change",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+// ApproveChange handles POST /api/admin/changes/:id/approve (admin endpoint)
+func (h *ModerationHandler) ApproveChange(c *gin.Context) {
+	h.reviewChange(c, h.service.ApproveChange)
+}
+
+// RejectChange handles POST /api/admin/changes/:id/reject (admin endpoint)
+func (h *ModerationHandler) RejectChange(c *gin.Context) {
+	h.reviewChange(c, h.service.RejectChange)
+}
+
+// reviewChange runs an approve or reject action for the authenticated moderator
+func (h *ModerationHandler) reviewChange(c *gin.Context, action func(int64, *models.User, string) (*models.PendingChange, error)) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid change ID",
+		})
+		return
+	}
+
+	// The note is optional, so an empty body is fine
+	var request models.ReviewChangeRequest
+	_ = c.ShouldBindJSON(&request)
+
+	change, err := action(id, user, request.Note)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "change not found" || err.Error() == "definition not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "change already reviewed" {
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"database/sql"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/words-api/words/internal/auth"
+	"github.com/words-api/words/internal/models"
+	"github.com/words-api/words/internal/services"
+)
+
+// maxQuizSeed keeps generated seeds exact in JavaScript numbers
+const maxQuizSeed = 1 << 53
+
+// QuizHandler handles HTTP requests for multiple-choice quizzes
+type QuizHandler struct {
+	service *services.QuizService
+}
+
+// NewQuizHandler creates a new quiz handler
+func NewQuizHandler(db *sql.DB) *QuizHandler {
+	return &QuizHandler{
+		service: services.NewQuizService(db),
+	}
+}
+
+// GetQuiz handles GET /api/quiz?count=10&seed=42 (authenticated endpoint). Without a seed a
+// random one is used; it is returned so the quiz can be built again.
+func (h *QuizHandler) GetQuiz(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	count, err := strconv.Atoi(c.DefaultQuery("count", "10"))
+	if err != nil || count < 1 || count > 50 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "count must be between 1 and 50",
+		})
+		return
+	}
+
+	seed := rand.Int64N(maxQuizSeed)
+	if value := c.Query("seed"); value != "" {
+		seed, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || seed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "seed must be a non-negative integer",
+			})
+			return
+		}
+	}
+
+	quiz, err := h.service.BuildQuiz(user.Username, count, seed)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "no words to quiz" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, quiz)
+}
+
+// AnswerQuiz handles POST /api/quiz/answer (authenticated endpoint)
+func (h *QuizHandler) AnswerQuiz(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	var request models.QuizAnswerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "word and definition_id are required",
+		})
+		return
+	}
+
+	result, err := h.service.Answer(user.Username, request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" || strings.HasPrefix(err.Error(), "word not found") ||
+			strings.HasPrefix(err.Error(), "word not in user's study list") {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "definition not found" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"database/sql"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/words-api/words/internal/auth"
@@ -32,7 +34,7 @@ func (h *ReviewHandler) GetDueWords(c *gin.Context) {
 		return
 	}
 
-	dueWords, err := h.service.GetDueWords(user.Username)
+	dueWords, quota, err := h.service.GetDueWords(user.Username)
 	if err != nil {
 		if err.Error() == "user not found" {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -50,6 +52,7 @@ func (h *ReviewHandler) GetDueWords(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"words": dueWords,
 		"count": len(dueWords),
+		"quota": quota,
 	})
 }
 
@@ -73,12 +76,12 @@ func (h *ReviewHandler) SubmitReview(c *gin.Context) {
 		return
 	}
 
-	updatedWord, err := h.service.SubmitReview(user.Username, word, request.Quality)
+	updatedWord, err := h.service.SubmitReview(user.Username, word, request.CardType, request.Quality)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "user not found" || err.Error() == "word not found" {
 			statusCode = http.StatusNotFound
-		} else if err.Error() == "quality must be between 0 and 5" {
+		} else if err.Error() == "quality must be between 0 and 5" || err.Error() == "unknown card type" {
 			statusCode = http.StatusBadRequest
 		}
 
@@ -91,6 +94,92 @@ func (h *ReviewHandler) SubmitReview(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedWord)
 }
 
+// GradeReview handles POST /api/review/:word/answer (authenticated endpoint)
+func (h *ReviewHandler) GradeReview(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	word := c.Param("word")
+
+	var request models.TypedAnswerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "typed answer is required",
+		})
+		return
+	}
+
+	responseTime := time.Duration(request.ResponseMS) * time.Millisecond
+	updatedWord, grade, err := h.service.GradeReview(user.Username, word, request.CardType, *request.Answer, responseTime)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" || strings.HasPrefix(err.Error(), "word not found") ||
+			strings.HasPrefix(err.Error(), "word not in user's study list") {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "unknown card type" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"grade":     grade,
+		"user_word": updatedWord,
+	})
+}
+
+// UndoReview handles POST /api/review/undo (authenticated endpoint)
+func (h *ReviewHandler) UndoReview(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	// The body is optional
+	var request models.UndoReviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid review ID",
+			})
+			return
+		}
+	}
+
+	review, userWord, err := h.service.UndoReview(user.Username, request.ReviewID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "user not found", "review not found", "word not in user's study list":
+			statusCode = http.StatusNotFound
+		case "only the latest review of a card can be undone", "review cannot be undone":
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"undone":    review,
+		"user_word": userWord,
+	})
+}
+
 // GetReviewHistory handles GET /api/review/:word/history (authenticated endpoint)
 func (h *ReviewHandler) GetReviewHistory(c *gin.Context) {
 	user, exists := auth.GetAuthenticatedUser(c)
@@ -123,3 +212,62 @@ func (h *ReviewHandler) GetReviewHistory(c *gin.Context) {
 		"count":   len(history),
 	})
 }
+
+// GetFSRSParameters handles GET /api/user/fsrs (authenticated endpoint)
+func (h *ReviewHandler) GetFSRSParameters(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	params, err := h.service.GetFSRSParameters(user.Username)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "user not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "failed to retrieve FSRS parameters",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, params)
+}
+
+// OptimizeFSRS handles POST /api/user/fsrs/optimize (authenticated endpoint). The fitted
+// weights are saved unless ?save=false.
+func (h *ReviewHandler) OptimizeFSRS(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	save := c.DefaultQuery("save", "true") != "false"
+
+	result, err := h.service.OptimizeFSRS(user.Username, save)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "not enough review history" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
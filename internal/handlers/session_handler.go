@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/words-api/words/internal/auth"
+	"github.com/words-api/words/internal/models"
+	"github.com/words-api/words/internal/services"
+)
+
+// SessionHandler handles HTTP requests for review sessions
+type SessionHandler struct {
+	service *services.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(db *sql.DB) *SessionHandler {
+	return &SessionHandler{
+		service: services.NewSessionService(db),
+	}
+}
+
+// sessionErrorStatus maps session service errors to HTTP status codes
+func sessionErrorStatus(err error) int {
+	switch err.Error() {
+	case "user not found", "session not found":
+		return http.StatusNotFound
+	case "no words due for review", "quality must be between 0 and 5", "unknown card type",
+		"give either a quality rating or a typed answer":
+		return http.StatusBadRequest
+	case "session has ended", "answer does not match the current card", "card already answered":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// sessionID parses the :id path parameter, responding with an error if it is invalid
+func sessionID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid session ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// CreateSession handles POST /api/review/sessions (authenticated endpoint)
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	// The body is optional
+	var request models.CreateSessionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be a non-negative number",
+			})
+			return
+		}
+	}
+
+	session, err := h.service.CreateSession(user.Username, request.Limit)
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// GetSessions handles GET /api/review/sessions (authenticated endpoint)
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit must be between 1 and 100",
+		})
+		return
+	}
+
+	sessions, err := h.service.GetSessions(user.Username, limit)
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+		"count":    len(sessions),
+	})
+}
+
+// GetSession handles GET /api/review/sessions/:id (authenticated endpoint)
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+	id, ok := sessionID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.service.GetSession(user.Username, id)
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// NextCard handles GET /api/review/sessions/:id/next (authenticated endpoint). Once the
+// session is over, card is null and done is true.
+func (h *SessionHandler) NextCard(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+	id, ok := sessionID(c)
+	if !ok {
+		return
+	}
+
+	card, session, err := h.service.NextCard(user.Username, id)
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"card":    card,
+		"done":    card == nil,
+		"session": session,
+	})
+}
+
+// Answer handles POST /api/review/sessions/:id/answer (authenticated endpoint)
+func (h *SessionHandler) Answer(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+	id, ok := sessionID(c)
+	if !ok {
+		return
+	}
+
+	var request models.SessionAnswerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "quality rating (0-5) or typed answer is required",
+		})
+		return
+	}
+
+	answer, err := h.service.Answer(user.Username, id, request)
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, answer)
+}
+
+// EndSession handles POST /api/review/sessions/:id/end (authenticated endpoint)
+func (h *SessionHandler) EndSession(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+	id, ok := sessionID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.service.EndSession(user.Username, id)
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
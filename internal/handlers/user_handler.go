@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/words-api/words/internal/auth"
+	"github.com/words-api/words/internal/models"
 	"github.com/words-api/words/internal/services"
 )
 
@@ -93,3 +94,174 @@ func (h *UserHandler) GetUserStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// GetScheduler handles GET /api/user/scheduler (authenticated endpoint)
+func (h *UserHandler) GetScheduler(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scheduler": user.Scheduler,
+		"available": services.SchedulerNames(),
+	})
+}
+
+// SetScheduler handles PUT /api/user/scheduler (authenticated endpoint)
+func (h *UserHandler) SetScheduler(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	var request models.SchedulerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "scheduler is required",
+		})
+		return
+	}
+
+	updated, err := h.service.SetScheduler(user.Username, request.Scheduler)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "unknown scheduler" {
+			statusCode = http.StatusBadRequest
+		} else if err.Error() == "user not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":     err.Error(),
+			"available": services.SchedulerNames(),
+		})
+		return
+	}
+
+	// The session holds the user loaded at login
+	user.Scheduler = updated.Scheduler
+	c.JSON(http.StatusOK, updated)
+}
+
+// GetLearningSteps handles GET /api/user/learning-steps (authenticated endpoint)
+func (h *UserHandler) GetLearningSteps(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"learning_steps":   user.LearningSteps,
+		"relearning_steps": user.RelearningSteps,
+	})
+}
+
+// SetLearningSteps handles PUT /api/user/learning-steps (authenticated endpoint)
+func (h *UserHandler) SetLearningSteps(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	var request models.LearningStepsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	updated, err := h.service.SetLearningSteps(user.Username, request.LearningSteps, request.RelearningSteps)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "learning steps must be durations from 1m to 7d", "too many learning steps":
+			statusCode = http.StatusBadRequest
+		case "user not found":
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// The session holds the user loaded at login
+	user.LearningSteps, user.RelearningSteps = updated.LearningSteps, updated.RelearningSteps
+	c.JSON(http.StatusOK, gin.H{
+		"learning_steps":   updated.LearningSteps,
+		"relearning_steps": updated.RelearningSteps,
+	})
+}
+
+// GetDailyLimits handles GET /api/user/daily-limits (authenticated endpoint)
+func (h *UserHandler) GetDailyLimits(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"new_per_day":     user.NewPerDay,
+		"reviews_per_day": user.ReviewsPerDay,
+	})
+}
+
+// SetDailyLimits handles PUT /api/user/daily-limits (authenticated endpoint)
+func (h *UserHandler) SetDailyLimits(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	var request models.DailyLimitsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	updated, err := h.service.SetDailyLimits(user.Username, request.NewPerDay, request.ReviewsPerDay)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case "daily limits must be between 0 and 9999":
+			statusCode = http.StatusBadRequest
+		case "user not found":
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// The session holds the user loaded at login
+	user.NewPerDay, user.ReviewsPerDay = updated.NewPerDay, updated.ReviewsPerDay
+	c.JSON(http.StatusOK, gin.H{
+		"new_per_day":     updated.NewPerDay,
+		"reviews_per_day": updated.ReviewsPerDay,
+	})
+}
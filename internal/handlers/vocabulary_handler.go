@@ -3,9 +3,11 @@ package handlers
 import (
 	"database/sql"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/words-api/words/internal/auth"
+	"github.com/words-api/words/internal/models"
 	"github.com/words-api/words/internal/services"
 )
 
@@ -33,12 +35,23 @@ func (h *VocabularyHandler) AddWord(c *gin.Context) {
 
 	word := c.Param("word")
 
-	userWord, err := h.service.AddWord(user.Username, word)
+	// The body is optional
+	var request models.CardTypesRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid card types",
+			})
+			return
+		}
+	}
+
+	cards, err := h.service.AddWord(user.Username, word, request.CardTypes)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "user not found" {
 			statusCode = http.StatusNotFound
-		} else if err.Error() == "word cannot be empty" {
+		} else if err.Error() == "word cannot be empty" || isCardTypeError(err) {
 			statusCode = http.StatusBadRequest
 		}
 
@@ -48,7 +61,10 @@ func (h *VocabularyHandler) AddWord(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, userWord)
+	c.JSON(http.StatusCreated, gin.H{
+		"cards": cards,
+		"count": len(cards),
+	})
 }
 
 // GetUserWords handles GET /api/words (authenticated endpoint)
@@ -83,3 +99,126 @@ func (h *VocabularyHandler) GetUserWords(c *gin.Context) {
 		"count": len(userWords),
 	})
 }
+
+// GetNotes handles GET /api/words/:word/notes (authenticated endpoint)
+func (h *VocabularyHandler) GetNotes(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	notes, err := h.service.GetNotes(user.Username, c.Param("word"))
+	if err != nil {
+		h.notesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// UpdateNotes handles PUT /api/words/:word/notes (authenticated endpoint)
+func (h *VocabularyHandler) UpdateNotes(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	var request models.WordNotesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid notes body",
+		})
+		return
+	}
+
+	notes, err := h.service.UpdateNotes(user.Username, c.Param("word"), request)
+	if err != nil {
+		h.notesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// GetCards handles GET /api/words/:word/cards (authenticated endpoint)
+func (h *VocabularyHandler) GetCards(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	cards, available, err := h.service.GetWordCards(user.Username, c.Param("word"))
+	if err != nil {
+		h.notesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cards":     cards,
+		"count":     len(cards),
+		"available": available,
+	})
+}
+
+// SetCards handles PUT /api/words/:word/cards (authenticated endpoint)
+func (h *VocabularyHandler) SetCards(c *gin.Context) {
+	user, exists := auth.GetAuthenticatedUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "not authenticated",
+		})
+		return
+	}
+
+	var request models.CardTypesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid card types",
+		})
+		return
+	}
+
+	cards, err := h.service.SetCardTypes(user.Username, c.Param("word"), request.CardTypes)
+	if err != nil {
+		h.notesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cards": cards,
+		"count": len(cards),
+	})
+}
+
+// isCardTypeError reports whether err rejects the card types asked for
+func isCardTypeError(err error) bool {
+	switch err.Error() {
+	case "unknown card type", "card type not available for this word", "at least one card type is required":
+		return true
+	}
+	return false
+}
+
+// notesError maps notes and card service errors to HTTP responses
+func (h *VocabularyHandler) notesError(c *gin.Context, err error) {
+	statusCode := http.StatusInternalServerError
+	if err.Error() == "user not found" || err.Error() == "word not in user's study list" ||
+		strings.HasPrefix(err.Error(), "word not found") {
+		statusCode = http.StatusNotFound
+	} else if err.Error() == "definition not found" || isCardTypeError(err) {
+		statusCode = http.StatusBadRequest
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error": err.Error(),
+	})
+}
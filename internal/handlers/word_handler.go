@@ -2,21 +2,26 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/words-api/words/internal/render"
 	"github.com/words-api/words/internal/services"
 )
 
 // WordHandler handles HTTP requests for word operations
 type WordHandler struct {
-	service *services.WordService
+	service        *services.WordService
+	exampleService *services.ExampleService
 }
 
 // NewWordHandler creates a new word handler
 func NewWordHandler(db *sql.DB) *WordHandler {
 	return &WordHandler{
-		service: services.NewWordService(db),
+		service:        services.NewWordService(db),
+		exampleService: services.NewExampleService(db),
 	}
 }
 
@@ -31,6 +36,27 @@ func (h *WordHandler) GetWord(c *gin.Context) {
 		return
 	}
 
+	format := negotiateWordFormat(c)
+	if format == "" {
+		c.JSON(http.StatusNotAcceptable, gin.H{
+			"error":     "unsupported format",
+			"supported": wordFormatOffers,
+		})
+		return
+	}
+	c.Header("Vary", "Accept")
+
+	// Answer conditional requests from the cheap version lookup before loading the full entry
+	id, updatedAt, versionErr := h.service.GetWordVersion(word)
+	if versionErr == nil {
+		etag := entityTag(id, updatedAt, format)
+		if notModified(c, etag, updatedAt) {
+			setCacheHeaders(c, etag, updatedAt)
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
 	result, err := h.service.GetWord(word)
 	if err != nil {
 		if err.Error() == "word not found" {
@@ -47,5 +73,116 @@ func (h *WordHandler) GetWord(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	// Words fetched from the external API are cached on the way through, so look the version up again
+	if versionErr != nil {
+		id, updatedAt, versionErr = h.service.GetWordVersion(word)
+	}
+	if versionErr == nil {
+		setCacheHeaders(c, entityTag(id, updatedAt, format), updatedAt)
+	}
+
+	switch format {
+	case gin.MIMEPlain:
+		c.String(http.StatusOK, render.Text(result))
+	case mimeMarkdown:
+		c.Data(http.StatusOK, mimeMarkdown+"; charset=utf-8", []byte(render.Markdown(result)))
+	case gin.MIMEHTML:
+		page, err := render.HTML(result)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "failed to render word",
+			})
+			return
+		}
+		c.Data(http.StatusOK, gin.MIMEHTML+"; charset=utf-8", []byte(page))
+	case mimeJSONLD:
+		c.Render(http.StatusOK, jsonLD{data: render.JSONLD(result)})
+	default:
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// GetExamples handles GET /api/words/:word/examples?limit=&offset=
+func (h *WordHandler) GetExamples(c *gin.Context) {
+	word := c.Param("word")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit must be between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "offset must be a non-negative integer",
+		})
+		return
+	}
+
+	examples, total, err := h.exampleService.GetExamples(word, limit, offset)
+	if err != nil {
+		if err.Error() == "word not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "word not found",
+				"word":  word,
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "failed to retrieve examples",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"word":     word,
+		"examples": examples,
+		"count":    len(examples),
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+const (
+	mimeMarkdown = "text/markdown"
+	mimeJSONLD   = "application/ld+json"
+)
+
+// wordFormatOffers lists the renderings of a word in server preference order
+var wordFormatOffers = []string{gin.MIMEJSON, gin.MIMEPlain, mimeMarkdown, gin.MIMEHTML, mimeJSONLD}
+
+// wordFormatAliases maps the ?format= override to a media type, for links where Accept can't be set
+var wordFormatAliases = map[string]string{
+	"json":     gin.MIMEJSON,
+	"text":     gin.MIMEPlain,
+	"markdown": mimeMarkdown,
+	"html":     gin.MIMEHTML,
+	"jsonld":   mimeJSONLD,
+}
+
+// negotiateWordFormat picks the media type for a word response, or "" if none is acceptable
+func negotiateWordFormat(c *gin.Context) string {
+	if alias := c.Query("format"); alias != "" {
+		return wordFormatAliases[alias]
+	}
+	return c.NegotiateFormat(wordFormatOffers...)
+}
+
+// jsonLD renders data as JSON with the JSON-LD content type
+type jsonLD struct {
+	data interface{}
+}
+
+func (r jsonLD) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return json.NewEncoder(w).Encode(r.data)
+}
+
+func (r jsonLD) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", mimeJSONLD+"; charset=utf-8")
 }
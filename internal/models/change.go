@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Change types a user can propose for a dictionary entry
+const (
+	ChangeAddDefinition  = "add_definition"
+	ChangeEditDefinition = "edit_definition"
+	ChangeAddExample     = "add_example"
+	ChangeAddSynonyms    = "add_synonyms"
+)
+
+// ChangeProposal holds the proposed content of a dictionary change
+type ChangeProposal struct {
+	PartOfSpeech string   `json:"part_of_speech,omitempty"`
+	Definition   string   `json:"definition,omitempty"`
+	Example      string   `json:"example,omitempty"`
+	Synonyms     []string `json:"synonyms,omitempty"`
+}
+
+// PendingChange represents a user-contributed change in the moderation queue
+type PendingChange struct {
+	ID           int64          `json:"id" db:"id"`
+	UserID       int64          `json:"user_id" db:"user_id"`
+	Username     string         `json:"username,omitempty"`
+	WordID       int64          `json:"word_id" db:"word_id"`
+	Word         string         `json:"word,omitempty"`
+	ChangeType   string         `json:"change_type" db:"change_type"`
+	DefinitionID int64          `json:"definition_id,omitempty" db:"definition_id"`
+	Proposal     ChangeProposal `json:"proposal" db:"payload"`
+	Status       string         `json:"status" db:"status"` // pending, approved, rejected
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	ReviewedBy   int64          `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt   *time.Time     `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewNote   string         `json:"review_note,omitempty" db:"review_note"`
+	Audit        []ChangeAudit  `json:"audit,omitempty"`
+}
+
+// ChangeAudit records one action taken on a pending change, including each row it modified
+type ChangeAudit struct {
+	ID        int64     `json:"id" db:"id"`
+	ChangeID  int64     `json:"change_id" db:"change_id"`
+	ActorID   int64     `json:"actor_id" db:"actor_id"`
+	Action    string    `json:"action" db:"action"` // proposed, approved, rejected, applied
+	TableName string    `json:"table_name,omitempty" db:"table_name"`
+	RowID     int64     `json:"row_id,omitempty" db:"row_id"`
+	OldValue  string    `json:"old_value,omitempty" db:"old_value"`
+	NewValue  string    `json:"new_value,omitempty" db:"new_value"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ProposeChangeRequest represents the JSON body for proposing a dictionary change
+type ProposeChangeRequest struct {
+	ChangeType   string `json:"change_type" binding:"required"`
+	DefinitionID int64  `json:"definition_id"`
+	ChangeProposal
+}
+
+// ReviewChangeRequest represents the JSON body for approving or rejecting a change
+type ReviewChangeRequest struct {
+	Note string `json:"note"`
+}
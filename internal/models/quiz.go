@@ -0,0 +1,38 @@
+package models
+
+// Quiz is a set of multiple-choice questions on a user's studied words. The same seed builds
+// the same quiz while the study list and dictionary are unchanged.
+type Quiz struct {
+	Seed      int64          `json:"seed"`
+	Questions []QuizQuestion `json:"questions"`
+	Count     int            `json:"count"`
+}
+
+// QuizQuestion asks which option defines a word; one option is the word's definition and the
+// others are distractors drawn from other words
+type QuizQuestion struct {
+	Word         string       `json:"word"`
+	PartOfSpeech string       `json:"part_of_speech"`
+	Options      []QuizOption `json:"options"`
+}
+
+// QuizOption is a definition offered as an answer
+type QuizOption struct {
+	DefinitionID int64  `json:"definition_id"`
+	Definition   string `json:"definition"`
+}
+
+// QuizAnswerRequest represents the JSON body for answering a quiz question
+type QuizAnswerRequest struct {
+	Word         string `json:"word" binding:"required"`
+	DefinitionID int64  `json:"definition_id" binding:"required"`
+	ResponseMS   int64  `json:"response_ms" binding:"min=0"` // time taken to answer, if measured
+}
+
+// QuizResult is the outcome of a quiz answer and the review it submitted
+type QuizResult struct {
+	Correct  bool       `json:"correct"`
+	Quality  int        `json:"quality"`
+	Answer   QuizOption `json:"answer"` // the word's definition
+	UserWord *UserWord  `json:"user_word"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// ReviewSession is a run through a queue of due words, with its progress so far
+type ReviewSession struct {
+	ID              int64      `json:"id" db:"id"`
+	UserID          int64      `json:"user_id" db:"user_id"`
+	StartedAt       time.Time  `json:"started_at" db:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+	Words           int        `json:"words"`     // distinct words queued
+	Cards           int        `json:"cards"`     // cards queued, counting failed words queued again
+	Answered        int        `json:"answered"`  // cards answered
+	Correct         int        `json:"correct"`   // answered with quality 3 or more
+	Failed          int        `json:"failed"`    // answered with quality below 3
+	Remaining       int        `json:"remaining"` // cards left to answer
+	AverageQuality  float64    `json:"average_quality"`
+	DurationSeconds int64      `json:"duration_seconds"` // until the session ended, or so far
+}
+
+// SessionCard is a word to review in a session, with everything needed to show it
+type SessionCard struct {
+	SessionID int64     `json:"session_id"`
+	Position  int       `json:"position"` // place in the queue, from 1
+	Requeued  bool      `json:"requeued"` // failed earlier in the session and shown again
+	CardType  string    `json:"card_type"`
+	Prompt    string    `json:"prompt"` // what the card asks
+	Answer    string    `json:"answer"` // what it expects, to reveal after answering
+	UserWord  *UserWord `json:"user_word"`
+	Entry     *Word     `json:"entry"` // the full dictionary entry
+}
+
+// SessionAnswer is the result of answering a session's current card
+type SessionAnswer struct {
+	UserWord *UserWord     `json:"user_word"`
+	Grade    *AnswerGrade  `json:"grade,omitempty"` // how a typed answer was graded
+	Requeued bool          `json:"requeued"`        // the word was failed and goes to the back of the queue
+	Session  ReviewSession `json:"session"`
+}
+
+// CreateSessionRequest represents the optional JSON body for starting a review session
+type CreateSessionRequest struct {
+	Limit int `json:"limit" binding:"min=0"` // most words to queue; 0 queues every word due
+}
+
+// SessionAnswerRequest represents the JSON body for answering a session's current card, with
+// either a quality rating or a typed answer for the server to grade. Word, if given, must be
+// the current card's word, so a stale client can't answer the wrong card.
+type SessionAnswerRequest struct {
+	Word       string  `json:"word"`
+	Quality    *int    `json:"quality" binding:"omitempty,min=0,max=5"`
+	Answer     *string `json:"answer"`
+	ResponseMS int64   `json:"response_ms" binding:"min=0"` // time taken to type the answer, if measured
+}
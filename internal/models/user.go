@@ -4,22 +4,65 @@ import "time"
 
 // User represents a user account
 type User struct {
-	ID        int64     `json:"id" db:"id"`
-	Username  string    `json:"username" db:"username"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID              int64     `json:"id" db:"id"`
+	Username        string    `json:"username" db:"username"`
+	Scheduler       string    `json:"scheduler" db:"scheduler"`               // review scheduling algorithm: sm2, leitner, fixed, fsrs
+	LearningSteps   []string  `json:"learning_steps" db:"learning_steps"`     // delays such as "1m" or "10m" a new word goes through before day-based intervals
+	RelearningSteps []string  `json:"relearning_steps" db:"relearning_steps"` // the same for a forgotten word
+	NewPerDay       int       `json:"new_per_day" db:"new_per_day"`           // words reviewed for the first time each day, at most
+	ReviewsPerDay   int       `json:"reviews_per_day" db:"reviews_per_day"`   // other words reviewed each day, at most
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 }
 
-// UserWord represents a word that a user is studying
+// Card types a word can be studied with
+const (
+	CardRecognition = "recognition" // word -> definition
+	CardRecall      = "recall"      // definition -> word
+	CardSynonym     = "synonym"     // word -> synonyms
+	CardCloze       = "cloze"       // example sentence with the word blanked out -> word
+	CardSpelling    = "spelling"    // pronunciation and definition -> spelling
+)
+
+// UserWord represents a card for a word that a user is studying; each card type the word is
+// studied with has its own row and schedule
 type UserWord struct {
-	ID             int64     `json:"id" db:"id"`
-	UserID         int64     `json:"user_id" db:"user_id"`
-	WordID         int64     `json:"word_id" db:"word_id"`
-	Word           string    `json:"word,omitempty"`
-	AddedAt        time.Time `json:"added_at" db:"added_at"`
-	Status         string    `json:"status" db:"status"` // learning, reviewing, mastered
-	NextReviewDate time.Time `json:"next_review_date" db:"next_review_date"`
-	EaseFactor     float64   `json:"ease_factor" db:"ease_factor"`
-	IntervalDays   int       `json:"interval_days" db:"interval_days"`
+	ID             int64      `json:"id" db:"id"`
+	UserID         int64      `json:"user_id" db:"user_id"`
+	WordID         int64      `json:"word_id" db:"word_id"`
+	Word           string     `json:"word,omitempty"`
+	CardType       string     `json:"card_type" db:"card_type"`
+	AddedAt        time.Time  `json:"added_at" db:"added_at"`
+	Status         string     `json:"status" db:"status"` // learning, reviewing, mastered
+	NextReviewDate time.Time  `json:"next_review_date" db:"next_review_date"`
+	EaseFactor     float64    `json:"ease_factor" db:"ease_factor"`
+	IntervalDays   int        `json:"interval_days" db:"interval_days"`
+	LeitnerBox     int        `json:"leitner_box" db:"leitner_box"`
+	LearningStep   int        `json:"learning_step,omitempty" db:"learning_step"` // current learning step, from 1; 0 once on day-based intervals
+	Relearning     bool       `json:"relearning,omitempty" db:"relearning"`       // the steps are relearning steps for a forgotten word
+	Stability      float64    `json:"stability,omitempty" db:"fsrs_stability"`    // FSRS: days until recall drops to 90%
+	Difficulty     float64    `json:"difficulty,omitempty" db:"fsrs_difficulty"`  // FSRS: 1 (easy) to 10 (hard)
+	Retrievability float64    `json:"retrievability,omitempty"`                   // FSRS: probability of recalling the word now
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty" db:"last_reviewed_at"`
+	ConfusableWith []string   `json:"confusable_with,omitempty"` // other words in the list that are easily mixed up with this one
+	Notes          *WordNotes `json:"notes,omitempty"`
+}
+
+// WordNotes holds a user's private notes for a word they are studying
+type WordNotes struct {
+	Notes                 string    `json:"notes,omitempty" db:"notes"`
+	Mnemonic              string    `json:"mnemonic,omitempty" db:"mnemonic"`
+	PreferredDefinitionID int64     `json:"preferred_definition_id,omitempty" db:"preferred_definition_id"`
+	PreferredDefinition   string    `json:"preferred_definition,omitempty"`
+	Examples              []string  `json:"examples,omitempty"`
+	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WordNotesRequest represents the JSON body for replacing a word's notes
+type WordNotesRequest struct {
+	Notes                 string   `json:"notes"`
+	Mnemonic              string   `json:"mnemonic"`
+	PreferredDefinitionID int64    `json:"preferred_definition_id"`
+	Examples              []string `json:"examples"`
 }
 
 // ReviewHistory represents a single review session
@@ -28,15 +71,98 @@ type ReviewHistory struct {
 	UserID       int64     `json:"user_id" db:"user_id"`
 	WordID       int64     `json:"word_id" db:"word_id"`
 	Word         string    `json:"word,omitempty"`
+	CardType     string    `json:"card_type" db:"card_type"`
 	ReviewedAt   time.Time `json:"reviewed_at" db:"reviewed_at"`
 	Quality      int       `json:"quality" db:"quality"` // 0-5 rating
 	IntervalDays int       `json:"interval_days" db:"interval_days"`
 	EaseFactor   float64   `json:"ease_factor" db:"ease_factor"`
 }
 
+// SchedulerRequest represents the JSON body for choosing a review scheduling algorithm
+type SchedulerRequest struct {
+	Scheduler string `json:"scheduler" binding:"required"`
+}
+
+// LearningStepsRequest represents the JSON body for changing learning steps; a list left out is
+// kept and an empty one disables those steps
+type LearningStepsRequest struct {
+	LearningSteps   []string `json:"learning_steps"`
+	RelearningSteps []string `json:"relearning_steps"`
+}
+
+// DailyLimitsRequest represents the JSON body for changing daily limits; a limit left out is kept
+type DailyLimitsRequest struct {
+	NewPerDay     *int `json:"new_per_day"`
+	ReviewsPerDay *int `json:"reviews_per_day"`
+}
+
+// ReviewQuota reports how much of a user's daily limits is left. Words in their learning steps
+// are always due and not counted.
+type ReviewQuota struct {
+	NewPerDay        int `json:"new_per_day"`
+	ReviewsPerDay    int `json:"reviews_per_day"`
+	NewToday         int `json:"new_today"`     // words reviewed for the first time today
+	ReviewsToday     int `json:"reviews_today"` // other words reviewed today
+	NewRemaining     int `json:"new_remaining"`
+	ReviewsRemaining int `json:"reviews_remaining"`
+}
+
+// FSRSParameters are the weights FSRS schedules a user's reviews with
+type FSRSParameters struct {
+	Weights          []float64 `json:"weights"`
+	Fitted           bool      `json:"fitted"` // false while the published defaults are in use
+	DesiredRetention float64   `json:"desired_retention"`
+}
+
+// FSRSOptimization reports FSRS weights fitted to a user's review history
+type FSRSOptimization struct {
+	Weights           []float64 `json:"weights"`
+	Reviews           int       `json:"reviews"` // reviews of a word seen before, which the weights predict
+	Words             int       `json:"words"`
+	LogLossBefore     float64   `json:"log_loss_before"` // with the weights in use until now
+	LogLoss           float64   `json:"log_loss"`
+	ActualRetention   float64   `json:"actual_retention"`   // share of those reviews recalled
+	ExpectedRetention float64   `json:"expected_retention"` // mean recall probability the fitted weights predict for them
+	DesiredRetention  float64   `json:"desired_retention"`  // what reviews are scheduled for
+	Saved             bool      `json:"saved"`
+}
+
+// UndoReviewRequest represents the optional JSON body for undoing a review; without a review ID
+// the most recent review is undone
+type UndoReviewRequest struct {
+	ReviewID int64 `json:"review_id" binding:"min=0"`
+}
+
 // ReviewRequest represents the JSON body for submitting a review
 type ReviewRequest struct {
-	Quality int `json:"quality" binding:"required,min=0,max=5"`
+	Quality  int    `json:"quality" binding:"required,min=0,max=5"`
+	CardType string `json:"card_type"` // defaults to recognition
+}
+
+// TypedAnswerRequest represents the JSON body for reviewing a card with a typed answer, which
+// the server grades. An empty answer means the user gave up.
+type TypedAnswerRequest struct {
+	Answer     *string `json:"answer" binding:"required"`
+	CardType   string  `json:"card_type"`                   // defaults to recognition
+	ResponseMS int64   `json:"response_ms" binding:"min=0"` // time taken to answer, if measured
+}
+
+// AnswerGrade explains how a typed answer was graded and the quality it was rated
+type AnswerGrade struct {
+	Answer      string  `json:"answer"`
+	Expected    string  `json:"expected"`               // the answer the card expects
+	MatchedWith string  `json:"matched_with,omitempty"` // the accepted answer closest to it
+	Match       string  `json:"match"`                  // exact, typo, synonym, definition, partial, wrong or blank
+	Similarity  float64 `json:"similarity"`             // 0-1
+	ResponseMS  int64   `json:"response_ms,omitempty"`
+	Slow        bool    `json:"slow"`
+	Quality     int     `json:"quality"`
+	Explanation string  `json:"explanation"`
+}
+
+// CardTypesRequest represents the JSON body for choosing which cards a word is studied with
+type CardTypesRequest struct {
+	CardTypes []string `json:"card_types"`
 }
 
 // UserStats represents learning statistics for a user
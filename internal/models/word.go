@@ -12,6 +12,21 @@ type Word struct {
 	Meanings    []Meaning `json:"meanings,omitempty"`
 	Phonetics   []Phonetic `json:"phonetics,omitempty"`
 	SourceUrls  []string  `json:"sourceUrls,omitempty"`
+	WordsetID   string    `json:"wordsetId,omitempty" db:"wordset_id"`
+	Credits     []Credit  `json:"credits,omitempty"`
+}
+
+// Credit roles
+const (
+	CreditEditor      = "editor"
+	CreditContributor = "contributor"
+)
+
+// Credit names someone an upstream dataset credits for an entry
+type Credit struct {
+	Name   string `json:"name" db:"name"`
+	Role   string `json:"role" db:"role"`
+	Source string `json:"source" db:"source"`
 }
 
 // Meaning represents a part of speech with its definitions
@@ -32,6 +47,7 @@ type Definition struct {
 	Example    string   `json:"example,omitempty" db:"example"`
 	Synonyms   []string `json:"synonyms,omitempty"`
 	Antonyms   []string `json:"antonyms,omitempty"`
+	ExternalID string   `json:"externalId,omitempty" db:"external_id"`
 }
 
 // Phonetic represents pronunciation information
@@ -67,3 +83,37 @@ type DictionaryAPIResponse []struct {
 	} `json:"license"`
 	SourceUrls []string `json:"sourceUrls"`
 }
+
+// ConfusablePair represents a curated pair of words learners commonly mix up
+type ConfusablePair struct {
+	WordA string `json:"word_a" db:"word_a"`
+	WordB string `json:"word_b" db:"word_b"`
+	Note  string `json:"note,omitempty" db:"note"`
+}
+
+// WordComparison represents two dictionary entries compared side by side
+type WordComparison struct {
+	A                   *Word           `json:"a"`
+	B                   *Word           `json:"b"`
+	SharedSynonyms      []string        `json:"shared_synonyms"`
+	DistinctSynonymsA   []string        `json:"distinct_synonyms_a"`
+	DistinctSynonymsB   []string        `json:"distinct_synonyms_b"`
+	SharedPartsOfSpeech []string        `json:"shared_parts_of_speech"`
+	EditDistance        int             `json:"edit_distance"`
+	PhoneticSimilarity  float64         `json:"phonetic_similarity"`
+	SoundexA            string          `json:"soundex_a"`
+	SoundexB            string          `json:"soundex_b"`
+	Confusable          *ConfusablePair `json:"confusable,omitempty"`
+}
+
+// CorpusExample represents a candidate example sentence mined from a text corpus
+type CorpusExample struct {
+	ID        int64     `json:"id" db:"id"`
+	WordID    int64     `json:"-" db:"word_id"`
+	Sentence  string    `json:"sentence" db:"sentence"`
+	Source    string    `json:"source" db:"source"`
+	Position  int       `json:"position" db:"position"` // token index of the headword in the sentence
+	Length    int       `json:"length" db:"length"`     // sentence length in tokens
+	Score     float64   `json:"score" db:"score"`       // quality heuristic, 0-1
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
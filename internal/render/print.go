@@ -0,0 +1,328 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// Letter returns the section a headword is printed under: its initial a-z letter in upper
+// case, or "#" for everything else. Words must reach a print document sorted by section
+// (symbols first) and then by headword.
+func Letter(word string) string {
+	if word != "" {
+		if c := word[0] | 0x20; c >= 'a' && c <= 'z' {
+			return string(c - 0x20)
+		}
+	}
+	return "#"
+}
+
+// PrintDocument typesets a stream of words into a printable dictionary, one section per
+// letter with the first and last headword on each page as running heads
+type PrintDocument struct {
+	w       *bufio.Writer
+	style   printStyle
+	letter  string
+	entries int
+	// sources counts entries per source site, for the colophon
+	sources map[string]int
+}
+
+// printStyle is the markup a PrintDocument is written in
+type printStyle interface {
+	begin(w io.Writer, title string) error
+	beginLetter(w io.Writer, letter string) error
+	entry(w io.Writer, word *models.Word) error
+	endLetter(w io.Writer) error
+	end(w io.Writer, sources []string) error
+}
+
+// NewLaTeX starts a LaTeX document modelled on Wordset's template.tex: two columns per letter,
+// running heads from \markboth. Compile it with xelatex or lualatex; pdflatex only copes with
+// Latin text.
+func NewLaTeX(out io.Writer, title string) (*PrintDocument, error) {
+	return newPrintDocument(out, title, latexStyle{})
+}
+
+// NewPrintHTML starts a self-contained HTML document laid out for printing. Browsers print it
+// in two columns with a page per letter; running heads need a paged-media renderer such as
+// WeasyPrint or Prince.
+func NewPrintHTML(out io.Writer, title string) (*PrintDocument, error) {
+	return newPrintDocument(out, title, printHTMLStyle{})
+}
+
+func newPrintDocument(out io.Writer, title string, style printStyle) (*PrintDocument, error) {
+	d := &PrintDocument{w: bufio.NewWriter(out), style: style, sources: make(map[string]int)}
+	if err := style.begin(d.w, title); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Write adds a word, opening a new letter section when its initial changes
+func (d *PrintDocument) Write(word *models.Word) error {
+	if letter := Letter(word.Word); letter != d.letter || d.entries == 0 {
+		if d.entries > 0 {
+			if err := d.style.endLetter(d.w); err != nil {
+				return err
+			}
+		}
+		if err := d.style.beginLetter(d.w, letter); err != nil {
+			return err
+		}
+		d.letter = letter
+	}
+
+	for _, u := range word.SourceUrls {
+		if parsed, err := url.Parse(u); err == nil && parsed.Host != "" {
+			d.sources[parsed.Host]++
+		}
+	}
+
+	d.entries++
+	return d.style.entry(d.w, word)
+}
+
+// Close ends the open section, adds the colophon and flushes the document
+func (d *PrintDocument) Close() error {
+	if d.entries > 0 {
+		if err := d.style.endLetter(d.w); err != nil {
+			return err
+		}
+	}
+
+	var sources []string
+	for host, n := range d.sources {
+		sources = append(sources, fmt.Sprintf("%s (%d %s)", host, n, plural(n, "entry", "entries")))
+	}
+	sort.Strings(sources)
+
+	if err := d.style.end(d.w, sources); err != nil {
+		return err
+	}
+	return d.w.Flush()
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+type latexStyle struct{}
+
+const latexPreamble = `%% Generated from the words database by cmd/exporter
+\documentclass[10pt,a4paper,twoside]{article}
+
+\usepackage[top=3.5cm,bottom=3.5cm,left=3.7cm,right=4.7cm,columnsep=30pt]{geometry}
+\usepackage{iftex}
+\ifPDFTeX
+  \usepackage[utf8]{inputenc}
+  \usepackage[T1]{fontenc}
+  \usepackage{palatino}
+\else
+  \usepackage{fontspec}
+\fi
+\usepackage{microtype}
+\usepackage{multicol}
+\usepackage[bf,sf,center]{titlesec}
+
+\usepackage{fancyhdr}
+\fancyhead[L]{\textsf{\rightmark}} %% First headword on the page
+\fancyhead[R]{\textsf{\leftmark}} %% Last headword on the page
+\renewcommand{\headrulewidth}{1.4pt}
+\fancyfoot[C]{\textbf{\textsf{\thepage}}}
+\renewcommand{\footrulewidth}{1.4pt}
+\pagestyle{fancy}
+
+%% Entry markup; redefine these to restyle the dictionary
+\newcommand{\headword}[1]{\par\markboth{#1}{#1}\textbf{#1}}
+\newcommand{\phonetic}[1]{\ #1}
+\newcommand{\pos}[1]{\ $\bullet$\ \textit{#1}}
+\newcommand{\sense}[1]{\ \textbf{#1}}
+\newcommand{\example}[1]{\ \textit{\textquotedblleft #1\textquotedblright}}
+\newcommand{\synonyms}[1]{\ \textsc{Synonyms:} #1}
+\newcommand{\antonyms}[1]{\ \textsc{Antonyms:} #1}
+
+\title{\textsf{%s}}
+\author{}
+\date{%s}
+
+\begin{document}
+\maketitle
+\thispagestyle{empty}
+\clearpage
+`
+
+func (latexStyle) begin(w io.Writer, title string) error {
+	_, err := fmt.Fprintf(w, latexPreamble, latexEscape(title), time.Now().Format("January 2006"))
+	return err
+}
+
+func (latexStyle) beginLetter(w io.Writer, letter string) error {
+	_, err := fmt.Fprintf(w, "\n\\section*{%s}\n\\begin{multicols}{2}\n", latexEscape(letter))
+	return err
+}
+
+func (latexStyle) entry(w io.Writer, word *models.Word) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\\headword{%s}", latexEscape(word.Word))
+	if word.Phonetic != "" {
+		fmt.Fprintf(&b, "\\phonetic{%s}", latexEscape(word.Phonetic))
+	}
+	for _, m := range word.Meanings {
+		fmt.Fprintf(&b, "\n\\pos{%s}", latexEscape(m.PartOfSpeech))
+		for i, d := range m.Definitions {
+			if len(m.Definitions) > 1 {
+				fmt.Fprintf(&b, "\\sense{%d.}", i+1)
+			}
+			fmt.Fprintf(&b, " %s", latexEscape(sentence(d.Definition)))
+			if d.Example != "" {
+				fmt.Fprintf(&b, "\\example{%s}", latexEscape(d.Example))
+			}
+			if synonyms := related(d.Synonyms); synonyms != "" {
+				fmt.Fprintf(&b, "\\synonyms{%s}", latexEscape(synonyms))
+			}
+		}
+		if synonyms := related(m.Synonyms); synonyms != "" {
+			fmt.Fprintf(&b, "\\synonyms{%s}", latexEscape(synonyms))
+		}
+		if antonyms := related(m.Antonyms); antonyms != "" {
+			fmt.Fprintf(&b, "\\antonyms{%s}", latexEscape(antonyms))
+		}
+	}
+	b.WriteString("\n\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (latexStyle) endLetter(w io.Writer) error {
+	_, err := io.WriteString(w, "\\end{multicols}\n\\clearpage\n")
+	return err
+}
+
+func (latexStyle) end(w io.Writer, sources []string) error {
+	if len(sources) > 0 {
+		fmt.Fprintf(w, "\n\\section*{Sources}\n%s.\n", latexEscape(strings.Join(sources, ", ")))
+	}
+	_, err := io.WriteString(w, "\n\\end{document}\n")
+	return err
+}
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "$", `\$`, "&", `\&`, "#", `\#`,
+	"^", `\textasciicircum{}`, "_", `\_`, "%", `\%`, "~", `\textasciitilde{}`,
+)
+
+// latexEscape escapes characters that LaTeX would otherwise interpret
+func latexEscape(s string) string {
+	return latexEscaper.Replace(s)
+}
+
+// related lists synonyms or antonyms, skipping blank ones
+func related(values []string) string {
+	var kept []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			kept = append(kept, v)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
+
+// sentence ends a definition with a full stop, as printed dictionaries do
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s[len(s)-1:], ".!?") {
+		return s
+	}
+	return s + "."
+}
+
+type printHTMLStyle struct{}
+
+var printHTMLTemplates = template.Must(template.New("begin").Funcs(template.FuncMap{
+	"inc":      func(i int) int { return i + 1 },
+	"sentence": sentence,
+	"related":  related,
+	"join":     strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+@page {
+  size: A4;
+  margin: 2.5cm 2cm;
+  @top-left { content: string(headword, first); font: bold 9pt sans-serif; }
+  @top-right { content: string(headword, last); font: bold 9pt sans-serif; }
+  @bottom-center { content: counter(page); font: bold 9pt sans-serif; }
+}
+body { font-family: Palatino, "Palatino Linotype", "TeX Gyre Pagella", Georgia, serif; font-size: 10pt; line-height: 1.35; color: #000; }
+@media screen { body { max-width: 60em; margin: 2em auto; padding: 0 1em; } }
+.title { text-align: center; font-family: sans-serif; margin: 30vh 0; break-after: page; }
+section.letter { break-before: page; columns: 2; column-gap: 2.5em; }
+section.letter:first-of-type { break-before: auto; }
+h2 { column-span: all; text-align: center; font-family: sans-serif; border-bottom: 1.4pt solid; }
+.entry { margin: 0 0 0.4em; text-align: justify; hyphens: auto; break-inside: avoid-column; }
+.headword { font-weight: bold; string-set: headword content(); }
+.pos, .example { font-style: italic; }
+.sense { font-weight: bold; }
+.label { font-variant: small-caps; }
+.colophon { break-before: page; font-size: 9pt; }
+</style>
+</head>
+<body>
+<div class="title"><h1>{{.Title}}</h1><p>{{.Date}}</p></div>
+{{define "letter"}}<section class="letter">
+<h2>{{.}}</h2>
+{{end}}
+{{- define "entry"}}<p class="entry"><span class="headword">{{.Word}}</span>
+{{- if .Phonetic}} <span class="phonetic">{{.Phonetic}}</span>{{end}}
+{{- range .Meanings}} &bull; <span class="pos">{{.PartOfSpeech}}</span>
+{{- $many := gt (len .Definitions) 1}}
+{{- range $i, $d := .Definitions}}{{if $many}} <span class="sense">{{inc $i}}.</span>{{end}} {{sentence $d.Definition}}
+{{- if $d.Example}} <span class="example">&ldquo;{{$d.Example}}&rdquo;</span>{{end}}
+{{- with related $d.Synonyms}} <span class="label">Synonyms:</span> {{.}}{{end}}
+{{- end}}
+{{- with related .Synonyms}} <span class="label">Synonyms:</span> {{.}}{{end}}
+{{- with related .Antonyms}} <span class="label">Antonyms:</span> {{.}}{{end}}
+{{- end}}</p>
+{{end}}
+{{- define "end"}}{{if .}}<section class="colophon"><h2>Sources</h2><p>{{join . ", "}}.</p></section>
+{{end}}</body>
+</html>
+{{end}}`))
+
+func (printHTMLStyle) begin(w io.Writer, title string) error {
+	return printHTMLTemplates.ExecuteTemplate(w, "begin", map[string]string{
+		"Title": title, "Date": time.Now().Format("January 2006"),
+	})
+}
+
+func (printHTMLStyle) beginLetter(w io.Writer, letter string) error {
+	return printHTMLTemplates.ExecuteTemplate(w, "letter", letter)
+}
+
+func (printHTMLStyle) entry(w io.Writer, word *models.Word) error {
+	return printHTMLTemplates.ExecuteTemplate(w, "entry", word)
+}
+
+func (printHTMLStyle) endLetter(w io.Writer) error {
+	_, err := io.WriteString(w, "</section>\n")
+	return err
+}
+
+func (printHTMLStyle) end(w io.Writer, sources []string) error {
+	return printHTMLTemplates.ExecuteTemplate(w, "end", sources)
+}
@@ -0,0 +1,260 @@
+// Package render formats dictionary entries for display outside the JSON API
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/words-api/words/internal/models"
+)
+
+const textWidth = 78
+
+// Text renders a word as terminal-friendly plain text
+func Text(w *models.Word) string {
+	var b strings.Builder
+
+	b.WriteString(w.Word)
+	if w.Phonetic != "" {
+		b.WriteString("  " + w.Phonetic)
+	}
+	b.WriteString("\n")
+
+	for _, m := range w.Meanings {
+		b.WriteString("\n" + m.PartOfSpeech + "\n")
+		for i, d := range m.Definitions {
+			prefix := fmt.Sprintf("  %d. ", i+1)
+			indent := strings.Repeat(" ", len(prefix))
+			b.WriteString(wrap(d.Definition, prefix, indent))
+			if d.Example != "" {
+				b.WriteString(wrap(`"`+d.Example+`"`, indent, indent))
+			}
+			if len(d.Synonyms) > 0 {
+				b.WriteString(wrap("synonyms: "+strings.Join(d.Synonyms, ", "), indent, indent))
+			}
+		}
+		if len(m.Synonyms) > 0 {
+			b.WriteString(wrap("synonyms: "+strings.Join(m.Synonyms, ", "), "  ", "  "))
+		}
+		if len(m.Antonyms) > 0 {
+			b.WriteString(wrap("antonyms: "+strings.Join(m.Antonyms, ", "), "  ", "  "))
+		}
+	}
+
+	if credits := creditLine(w); credits != "" {
+		b.WriteString("\n" + wrap(credits, "", ""))
+	}
+
+	return b.String()
+}
+
+// Markdown renders a word as a Markdown fragment
+func Markdown(w *models.Word) string {
+	var b strings.Builder
+
+	b.WriteString("# " + escapeMarkdown(w.Word) + "\n")
+	if w.Phonetic != "" {
+		b.WriteString("\n`" + w.Phonetic + "`\n")
+	}
+
+	for _, m := range w.Meanings {
+		b.WriteString("\n## " + escapeMarkdown(m.PartOfSpeech) + "\n\n")
+		for i, d := range m.Definitions {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, escapeMarkdown(d.Definition))
+			if d.Example != "" {
+				b.WriteString("   > " + escapeMarkdown(d.Example) + "\n")
+			}
+			if len(d.Synonyms) > 0 {
+				b.WriteString("\n   *Synonyms:* " + escapeMarkdown(strings.Join(d.Synonyms, ", ")) + "\n")
+			}
+		}
+		if len(m.Synonyms) > 0 {
+			b.WriteString("\n**Synonyms:** " + escapeMarkdown(strings.Join(m.Synonyms, ", ")) + "\n")
+		}
+		if len(m.Antonyms) > 0 {
+			b.WriteString("\n**Antonyms:** " + escapeMarkdown(strings.Join(m.Antonyms, ", ")) + "\n")
+		}
+	}
+
+	credits := creditLine(w)
+	if len(w.SourceUrls) > 0 || credits != "" {
+		b.WriteString("\n---\n")
+	}
+	if credits != "" {
+		b.WriteString("\n" + escapeMarkdown(credits) + "\n")
+	}
+	if len(w.SourceUrls) > 0 {
+		b.WriteString("\nSources: ")
+		for i, url := range w.SourceUrls {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "<%s>", url)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("word").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Word}}</title>
+<style>
+body { font-family: Georgia, serif; max-width: 40em; margin: 2em auto; padding: 0 1em; line-height: 1.5; color: #222; }
+h1 { margin-bottom: 0; }
+.phonetic { color: #666; }
+h2 { font-style: italic; font-weight: normal; font-size: 1.1em; margin-top: 1.5em; }
+.example { color: #555; font-style: italic; }
+.related { font-size: 0.9em; color: #444; }
+footer { margin-top: 2em; font-size: 0.8em; color: #777; }
+</style>
+</head>
+<body>
+<article>
+<h1>{{.Word}}</h1>
+{{- if .Phonetic}}
+<div class="phonetic">{{.Phonetic}}</div>
+{{- end}}
+{{- range .Meanings}}
+<section>
+<h2>{{.PartOfSpeech}}</h2>
+<ol>
+{{- range .Definitions}}
+<li>{{.Definition}}
+{{- if .Example}}<div class="example">&ldquo;{{.Example}}&rdquo;</div>{{end}}
+{{- if .Synonyms}}<div class="related">Synonyms: {{range $i, $s := .Synonyms}}{{if $i}}, {{end}}{{$s}}{{end}}</div>{{end}}
+</li>
+{{- end}}
+</ol>
+{{- if .Synonyms}}
+<p class="related">Synonyms: {{range $i, $s := .Synonyms}}{{if $i}}, {{end}}{{$s}}{{end}}</p>
+{{- end}}
+{{- if .Antonyms}}
+<p class="related">Antonyms: {{range $i, $s := .Antonyms}}{{if $i}}, {{end}}{{$s}}{{end}}</p>
+{{- end}}
+</section>
+{{- end}}
+{{- if or .SourceUrls .Credits}}
+<footer>
+{{- with .Credits}}<p>{{.}}</p>{{end}}
+{{- with .SourceUrls}}<p>Sources: {{range $i, $u := .}}{{if $i}}, {{end}}<a href="{{$u}}">{{$u}}</a>{{end}}</p>{{end}}
+</footer>
+{{- end}}
+</article>
+</body>
+</html>
+`))
+
+// HTML renders a word as a standalone HTML document
+func HTML(w *models.Word) (string, error) {
+	var buf bytes.Buffer
+	data := struct {
+		*models.Word
+		Credits string
+	}{w, creditLine(w)}
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// JSONLD renders a word as a schema.org DefinedTermSet with one DefinedTerm per sense
+func JSONLD(w *models.Word) map[string]interface{} {
+	terms := []map[string]interface{}{}
+	for _, m := range w.Meanings {
+		for _, d := range m.Definitions {
+			term := map[string]interface{}{
+				"@type":                     "DefinedTerm",
+				"name":                      w.Word,
+				"description":               d.Definition,
+				"disambiguatingDescription": m.PartOfSpeech,
+			}
+			if d.ID > 0 {
+				term["termCode"] = fmt.Sprintf("%d", d.ID)
+			}
+			terms = append(terms, term)
+		}
+	}
+
+	doc := map[string]interface{}{
+		"@context":       "https://schema.org",
+		"@type":          "DefinedTermSet",
+		"name":           w.Word,
+		"hasDefinedTerm": terms,
+	}
+	if len(w.SourceUrls) > 0 {
+		doc["sameAs"] = w.SourceUrls
+	}
+	if w.WordsetID != "" {
+		doc["identifier"] = w.WordsetID
+	}
+	for _, role := range []string{models.CreditEditor, models.CreditContributor} {
+		var people []map[string]interface{}
+		for _, name := range creditNames(w, role) {
+			people = append(people, map[string]interface{}{"@type": "Person", "name": name})
+		}
+		if len(people) > 0 {
+			doc[role] = people
+		}
+	}
+	return doc
+}
+
+// creditNames lists the names credited with role, in stored order
+func creditNames(w *models.Word, role string) []string {
+	var names []string
+	for _, c := range w.Credits {
+		if c.Role == role {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// creditLine summarizes the credits as one sentence, or "" if there are none
+func creditLine(w *models.Word) string {
+	var parts []string
+	if editors := creditNames(w, models.CreditEditor); len(editors) > 0 {
+		parts = append(parts, "Edited by "+strings.Join(editors, ", ")+".")
+	}
+	if contributors := creditNames(w, models.CreditContributor); len(contributors) > 0 {
+		parts = append(parts, "Contributors: "+strings.Join(contributors, ", ")+".")
+	}
+	return strings.Join(parts, " ")
+}
+
+// wrap word-wraps text to textWidth, prefixing the first line and indenting the rest
+func wrap(text, prefix, indent string) string {
+	var b strings.Builder
+	line := prefix
+	lineHasWord := false
+	for _, word := range strings.Fields(text) {
+		if lineHasWord && len(line)+1+len(word) > textWidth {
+			b.WriteString(line + "\n")
+			line = indent
+			lineHasWord = false
+		}
+		if lineHasWord {
+			line += " "
+		}
+		line += word
+		lineHasWord = true
+	}
+	b.WriteString(line + "\n")
+	return b.String()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+// escapeMarkdown escapes characters that Markdown would otherwise interpret
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// CardTypes lists the card types a word can be studied with
+var CardTypes = []string{
+	models.CardRecognition, models.CardRecall, models.CardSynonym, models.CardCloze, models.CardSpelling,
+}
+
+// clozeBlank replaces the word in cloze sentences and in definitions that use it
+const clozeBlank = "_____"
+
+// normalizeCardTypes validates card types and drops duplicates; an empty list means a
+// recognition card
+func normalizeCardTypes(cardTypes []string) ([]string, error) {
+	if len(cardTypes) == 0 {
+		return []string{models.CardRecognition}, nil
+	}
+
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, cardType := range cardTypes {
+		cardType = strings.ToLower(strings.TrimSpace(cardType))
+		if !slices.Contains(CardTypes, cardType) {
+			return nil, fmt.Errorf("unknown card type")
+		}
+		if !seen[cardType] {
+			seen[cardType] = true
+			normalized = append(normalized, cardType)
+		}
+	}
+	return normalized, nil
+}
+
+// availableCardTypes returns the card types an entry has the content for: synonym cards need
+// synonyms, and cloze cards an example sentence that uses the word
+func availableCardTypes(entry *models.Word, notes *models.WordNotes) []string {
+	var available []string
+	for _, cardType := range CardTypes {
+		switch {
+		case cardType == models.CardSynonym && len(entrySynonyms(entry)) == 0:
+		case cardType == models.CardCloze && clozeSentence(entry, notes) == "":
+		default:
+			available = append(available, cardType)
+		}
+	}
+	return available
+}
+
+// entrySynonyms collects an entry's synonyms from all its meanings and definitions
+func entrySynonyms(entry *models.Word) []string {
+	seen := make(map[string]bool)
+	var synonyms []string
+	add := func(words []string) {
+		for _, word := range words {
+			if key := strings.ToLower(word); !seen[key] && !strings.EqualFold(word, entry.Word) {
+				seen[key] = true
+				synonyms = append(synonyms, word)
+			}
+		}
+	}
+	for _, meaning := range entry.Meanings {
+		add(meaning.Synonyms)
+		for _, definition := range meaning.Definitions {
+			add(definition.Synonyms)
+		}
+	}
+	return synonyms
+}
+
+// clozeSentence returns the first example that uses the word, from the user's own examples
+// then the dictionary's, with the word blanked out, or "" if there is none
+func clozeSentence(entry *models.Word, notes *models.WordNotes) string {
+	var examples []string
+	if notes != nil {
+		examples = append(examples, notes.Examples...)
+	}
+	for _, meaning := range entry.Meanings {
+		for _, definition := range meaning.Definitions {
+			examples = append(examples, definition.Example)
+		}
+	}
+
+	for _, example := range examples {
+		if blanked, ok := blankWord(example, entry.Word); ok {
+			return blanked
+		}
+	}
+	return ""
+}
+
+// blankWord replaces each whole-word use of word in text, ignoring case
+func blankWord(text, word string) (string, bool) {
+	pattern, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(word) + `\b`)
+	if err != nil || !pattern.MatchString(text) {
+		return text, false
+	}
+	return pattern.ReplaceAllString(text, clozeBlank), true
+}
+
+// cardDefinition is the definition a card shows: the user's preferred one, else the first
+func cardDefinition(entry *models.Word, notes *models.WordNotes) string {
+	if notes != nil && notes.PreferredDefinition != "" {
+		return notes.PreferredDefinition
+	}
+	for _, meaning := range entry.Meanings {
+		for _, definition := range meaning.Definitions {
+			if definition.Definition != "" {
+				return definition.Definition
+			}
+		}
+	}
+	return ""
+}
+
+// cardFace returns what a card asks and the answer it expects. Cards answered with the word
+// blank it out of the definition they show.
+func cardFace(cardType string, entry *models.Word, notes *models.WordNotes) (prompt, answer string) {
+	definition := cardDefinition(entry, notes)
+	hidden, _ := blankWord(definition, entry.Word)
+
+	switch cardType {
+	case models.CardRecall:
+		return hidden, entry.Word
+	case models.CardSynonym:
+		return entry.Word, strings.Join(entrySynonyms(entry), ", ")
+	case models.CardCloze:
+		return clozeSentence(entry, notes), entry.Word
+	case models.CardSpelling:
+		if entry.Phonetic != "" {
+			return entry.Phonetic + " " + hidden, entry.Word
+		}
+		return hidden, entry.Word
+	default:
+		return entry.Word, definition
+	}
+}
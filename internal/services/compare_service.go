@@ -0,0 +1,168 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// CompareService handles business logic for comparing confusable words
+type CompareService struct {
+	db          *sql.DB
+	wordService *WordService
+}
+
+// NewCompareService creates a new compare service
+func NewCompareService(db *sql.DB) *CompareService {
+	return &CompareService{
+		db:          db,
+		wordService: NewWordService(db),
+	}
+}
+
+// Compare retrieves two words and compares them side by side
+func (s *CompareService) Compare(a, b string) (*models.WordComparison, error) {
+	a = strings.ToLower(strings.TrimSpace(a))
+	b = strings.ToLower(strings.TrimSpace(b))
+	if a == "" || b == "" {
+		return nil, fmt.Errorf("both words are required")
+	}
+	if a == b {
+		return nil, fmt.Errorf("words must be different")
+	}
+
+	wordA, err := s.wordService.GetWord(a)
+	if err != nil {
+		return nil, fmt.Errorf("word not found: %w", err)
+	}
+
+	wordB, err := s.wordService.GetWord(b)
+	if err != nil {
+		return nil, fmt.Errorf("word not found: %w", err)
+	}
+
+	synonymsA := collectSynonyms(wordA)
+	synonymsB := collectSynonyms(wordB)
+
+	comparison := &models.WordComparison{
+		A:                   wordA,
+		B:                   wordB,
+		SharedSynonyms:      []string{},
+		DistinctSynonymsA:   []string{},
+		DistinctSynonymsB:   []string{},
+		SharedPartsOfSpeech: []string{},
+		EditDistance:        levenshtein(a, b),
+		PhoneticSimilarity:  phoneticSimilarity(a, b, wordA.Phonetic, wordB.Phonetic),
+		SoundexA:            soundex(a),
+		SoundexB:            soundex(b),
+	}
+
+	for syn := range synonymsA {
+		if synonymsB[syn] {
+			comparison.SharedSynonyms = append(comparison.SharedSynonyms, syn)
+		} else {
+			comparison.DistinctSynonymsA = append(comparison.DistinctSynonymsA, syn)
+		}
+	}
+	for syn := range synonymsB {
+		if !synonymsA[syn] {
+			comparison.DistinctSynonymsB = append(comparison.DistinctSynonymsB, syn)
+		}
+	}
+	sort.Strings(comparison.SharedSynonyms)
+	sort.Strings(comparison.DistinctSynonymsA)
+	sort.Strings(comparison.DistinctSynonymsB)
+
+	posB := make(map[string]bool)
+	for _, m := range wordB.Meanings {
+		posB[m.PartOfSpeech] = true
+	}
+	seen := make(map[string]bool)
+	for _, m := range wordA.Meanings {
+		if posB[m.PartOfSpeech] && !seen[m.PartOfSpeech] {
+			comparison.SharedPartsOfSpeech = append(comparison.SharedPartsOfSpeech, m.PartOfSpeech)
+			seen[m.PartOfSpeech] = true
+		}
+	}
+	sort.Strings(comparison.SharedPartsOfSpeech)
+
+	comparison.Confusable, err = s.GetConfusablePair(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return comparison, nil
+}
+
+// GetConfusablePair looks up a curated confusable pair, returning nil if the words are not listed
+func (s *CompareService) GetConfusablePair(a, b string) (*models.ConfusablePair, error) {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if a > b {
+		a, b = b, a
+	}
+
+	pair := &models.ConfusablePair{}
+	var note sql.NullString
+	err := s.db.QueryRow(`
+		SELECT word_a, word_b, note FROM confusable_pairs WHERE word_a = ? AND word_b = ?
+	`, a, b).Scan(&pair.WordA, &pair.WordB, &note)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get confusable pair: %w", err)
+	}
+	pair.Note = note.String
+
+	return pair, nil
+}
+
+// GetConfusablesInList finds curated confusable pairs where both words are in the user's study list.
+// Returns a map of word ID to the other words it is easily confused with.
+func (s *CompareService) GetConfusablesInList(userID int64) (map[int64][]string, error) {
+	rows, err := s.db.Query(`
+		SELECT wa.id, wa.word, wb.id, wb.word
+		FROM confusable_pairs cp
+		JOIN words wa ON wa.word = cp.word_a
+		JOIN words wb ON wb.word = cp.word_b
+		WHERE EXISTS (SELECT 1 FROM user_words WHERE word_id = wa.id AND user_id = ?)
+		  AND EXISTS (SELECT 1 FROM user_words WHERE word_id = wb.id AND user_id = ?)
+	`, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get confusable pairs: %w", err)
+	}
+	defer rows.Close()
+
+	partners := make(map[int64][]string)
+	for rows.Next() {
+		var idA, idB int64
+		var wordA, wordB string
+		if err := rows.Scan(&idA, &wordA, &idB, &wordB); err != nil {
+			return nil, fmt.Errorf("failed to scan confusable pair: %w", err)
+		}
+		partners[idA] = append(partners[idA], wordB)
+		partners[idB] = append(partners[idB], wordA)
+	}
+
+	return partners, nil
+}
+
+// collectSynonyms gathers meaning- and definition-level synonyms as a lowercase set
+func collectSynonyms(word *models.Word) map[string]bool {
+	synonyms := make(map[string]bool)
+	for _, m := range word.Meanings {
+		for _, syn := range m.Synonyms {
+			synonyms[strings.ToLower(syn)] = true
+		}
+		for _, d := range m.Definitions {
+			for _, syn := range d.Synonyms {
+				synonyms[strings.ToLower(syn)] = true
+			}
+		}
+	}
+	return synonyms
+}
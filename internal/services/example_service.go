@@ -0,0 +1,66 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// ExampleService handles business logic for corpus example sentences
+type ExampleService struct {
+	db          *sql.DB
+	wordService *WordService
+}
+
+// NewExampleService creates a new example service
+func NewExampleService(db *sql.DB) *ExampleService {
+	return &ExampleService{
+		db:          db,
+		wordService: NewWordService(db),
+	}
+}
+
+// GetExamples retrieves a page of mined example sentences for a word, best first.
+// Returns the examples and the total number available.
+func (s *ExampleService) GetExamples(wordStr string, limit, offset int) ([]models.CorpusExample, int, error) {
+	wordID, _, err := s.wordService.GetWordVersion(wordStr)
+	if err == sql.ErrNoRows {
+		return nil, 0, fmt.Errorf("word not found")
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get word: %w", err)
+	}
+
+	var total int
+	err = s.db.QueryRow(`
+		SELECT COUNT(*) FROM corpus_examples WHERE word_id = ?
+	`, wordID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count examples: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, word_id, sentence, source, position, length, score, created_at
+		FROM corpus_examples
+		WHERE word_id = ?
+		ORDER BY score DESC, length ASC, id ASC
+		LIMIT ? OFFSET ?
+	`, wordID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get examples: %w", err)
+	}
+	defer rows.Close()
+
+	examples := []models.CorpusExample{}
+	for rows.Next() {
+		var e models.CorpusExample
+		if err := rows.Scan(&e.ID, &e.WordID, &e.Sentence, &e.Source, &e.Position,
+			&e.Length, &e.Score, &e.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan example: %w", err)
+		}
+		examples = append(examples, e)
+	}
+
+	return examples, total, nil
+}
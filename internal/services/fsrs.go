@@ -0,0 +1,251 @@
+package services
+
+import (
+	"math"
+	"time"
+)
+
+// FSRS (Free Spaced Repetition Scheduler) version 4.5. Each word has a stability S, the
+// interval in days after which recall drops to 90%, and a difficulty D from 1 to 10. Their
+// updates after a review are governed by 17 weights, which can be fitted to a user's reviews.
+// See https://github.com/open-spaced-repetition/fsrs4anki/wiki/The-Algorithm
+
+// DefaultFSRSWeights were fitted by the FSRS authors to a large body of Anki reviews
+var DefaultFSRSWeights = []float64{
+	0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031, 1.6474,
+	0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+}
+
+// fsrsWeightBounds keep fitted weights in the ranges the FSRS optimizer allows
+var fsrsWeightBounds = [][2]float64{
+	{0.1, 100}, {0.1, 100}, {0.1, 100}, {0.1, 100}, {1, 10}, {0.1, 5}, {0.1, 5}, {0, 0.5},
+	{0, 3}, {0.1, 0.8}, {0.01, 2.5}, {0.5, 5}, {0.01, 0.2}, {0.01, 0.9}, {0.01, 2}, {0, 1}, {1, 4},
+}
+
+// DesiredRetention is the recall probability FSRS schedules reviews for
+const DesiredRetention = 0.9
+
+// Forgetting curve R(t, S) = (1 + factor * t / S) ^ decay, with R(S, S) = 0.9
+const (
+	fsrsDecay  = -0.5
+	fsrsFactor = 19.0 / 81
+)
+
+// Grades FSRS rates reviews with
+const (
+	gradeAgain = 1
+	gradeHard  = 2
+	gradeGood  = 3
+	gradeEasy  = 4
+)
+
+// fsrsGrade maps a 0-5 quality rating to an FSRS grade: 0-2 forgot, 3 hard, 4 good, 5 easy
+func fsrsGrade(quality int) int {
+	switch {
+	case quality < 3:
+		return gradeAgain
+	case quality == 3:
+		return gradeHard
+	case quality == 4:
+		return gradeGood
+	default:
+		return gradeEasy
+	}
+}
+
+// fsrsRetrievability is the probability of recalling a word elapsedDays after its last
+// review
+func fsrsRetrievability(elapsedDays, stability float64) float64 {
+	return math.Pow(1+fsrsFactor*elapsedDays/stability, fsrsDecay)
+}
+
+// fsrsInterval is the number of days until recall drops to the desired retention
+func fsrsInterval(stability, retention float64) int {
+	days := stability / fsrsFactor * (math.Pow(retention, 1/fsrsDecay) - 1)
+	return min(max(int(math.Round(days)), 1), 36500)
+}
+
+// fsrsModel applies one set of weights
+type fsrsModel []float64
+
+func (w fsrsModel) initialDifficulty(grade int) float64 {
+	return clampDifficulty(w[4] - float64(grade-3)*w[5])
+}
+
+// first returns the state after a word's first review
+func (w fsrsModel) first(grade int) (stability, difficulty float64) {
+	return w[grade-1], w.initialDifficulty(grade)
+}
+
+// next returns the state after a review elapsedDays after the previous one
+func (w fsrsModel) next(stability, difficulty, elapsedDays float64, grade int) (float64, float64) {
+	r := fsrsRetrievability(elapsedDays, stability)
+
+	newDifficulty := difficulty - w[6]*float64(grade-3)
+	// Mean reversion toward the difficulty of a word first graded good
+	newDifficulty = clampDifficulty(w[7]*w.initialDifficulty(gradeGood) + (1-w[7])*newDifficulty)
+
+	if grade == gradeAgain {
+		forget := w[11] * math.Pow(difficulty, -w[12]) * (math.Pow(stability+1, w[13]) - 1) * math.Exp(w[14]*(1-r))
+		return max(min(forget, stability), 0.01), newDifficulty
+	}
+
+	bonus := 1.0
+	if grade == gradeHard {
+		bonus = w[15]
+	} else if grade == gradeEasy {
+		bonus = w[16]
+	}
+	growth := math.Exp(w[8]) * (11 - difficulty) * math.Pow(stability, -w[9]) * (math.Exp(w[10]*(1-r)) - 1) * bonus
+	return stability * (1 + growth), newDifficulty
+}
+
+func clampDifficulty(d float64) float64 {
+	return min(max(d, 1), 10)
+}
+
+// FSRSScheduler schedules each review for when recall is predicted to drop to
+// DesiredRetention. Weights defaults to DefaultFSRSWeights.
+type FSRSScheduler struct {
+	Weights []float64
+}
+
+func (FSRSScheduler) Name() string { return "fsrs" }
+
+func (f FSRSScheduler) Next(state CardState, quality int, now time.Time) CardState {
+	w := fsrsModel(f.Weights)
+	if len(w) != len(DefaultFSRSWeights) {
+		w = DefaultFSRSWeights
+	}
+	grade := fsrsGrade(quality)
+
+	switch {
+	case state.Stability > 0:
+		state.Stability, state.Difficulty = w.next(state.Stability, state.Difficulty, daysSince(state.LastReview, now), grade)
+	case state.Status != StatusLearning && state.IntervalDays > 1:
+		// Switched from another algorithm: its interval was meant to keep recall high, so it
+		// stands in for the stability, with an average difficulty
+		state.Stability, state.Difficulty = w.next(float64(state.IntervalDays), w.initialDifficulty(gradeGood), daysSince(state.LastReview, now), grade)
+	default:
+		state.Stability, state.Difficulty = w.first(grade)
+	}
+
+	state.IntervalDays = fsrsInterval(state.Stability, DesiredRetention)
+	if grade == gradeAgain {
+		state.Status = StatusLearning
+	} else {
+		state.Status = statusForInterval(state.IntervalDays)
+	}
+	return state
+}
+
+// minFSRSReviews is how many repeat reviews fitting weights needs
+const minFSRSReviews = 32
+
+// fsrsPrior pulls fitted weights toward the defaults, as if that many reviews agreed with
+// them, so a short history can't produce extreme weights
+const fsrsPrior = 8.0
+
+// fsrsReview is a past review replayed by the optimizer
+type fsrsReview struct {
+	at    time.Time
+	grade int
+}
+
+// fsrsFit measures how well weights predict a review history
+type fsrsFit struct {
+	// reviews counts the reviews of a word seen before, which are the ones predicted
+	reviews  int
+	recalled int
+	// logLoss is the mean log loss of the predicted recall probabilities
+	logLoss   float64
+	predicted float64
+}
+
+// evaluateFSRS replays each word's reviews in order, predicting every review after the first
+func evaluateFSRS(w fsrsModel, histories [][]fsrsReview) fsrsFit {
+	var fit fsrsFit
+	for _, history := range histories {
+		stability, difficulty := w.first(history[0].grade)
+		for i := 1; i < len(history); i++ {
+			review := history[i]
+			elapsed := daysSince(history[i-1].at, review.at)
+
+			r := min(max(fsrsRetrievability(elapsed, stability), 1e-6), 1-1e-6)
+			if review.grade == gradeAgain {
+				fit.logLoss -= math.Log(1 - r)
+			} else {
+				fit.logLoss -= math.Log(r)
+				fit.recalled++
+			}
+			fit.predicted += r
+			fit.reviews++
+
+			stability, difficulty = w.next(stability, difficulty, elapsed, review.grade)
+		}
+	}
+	if fit.reviews > 0 {
+		fit.logLoss /= float64(fit.reviews)
+		fit.predicted /= float64(fit.reviews)
+	}
+	return fit
+}
+
+// fitFSRS fits weights to the histories by gradient descent (Adam) on the log loss, starting
+// from the defaults. Weights are optimized scaled to [0, 1] within their bounds, so one
+// learning rate suits them all.
+func fitFSRS(histories [][]fsrsReview, reviews int) []float64 {
+	const (
+		iterations = 200
+		rate       = 0.01
+		step       = 1e-4
+		beta1      = 0.9
+		beta2      = 0.999
+	)
+
+	n := len(DefaultFSRSWeights)
+	scale := func(x []float64) fsrsModel {
+		w := make(fsrsModel, n)
+		for i, b := range fsrsWeightBounds {
+			w[i] = b[0] + x[i]*(b[1]-b[0])
+		}
+		return w
+	}
+	start := make([]float64, n)
+	for i, b := range fsrsWeightBounds {
+		start[i] = (DefaultFSRSWeights[i] - b[0]) / (b[1] - b[0])
+	}
+	objective := func(x []float64) float64 {
+		penalty := 0.0
+		for i := range x {
+			penalty += (x[i] - start[i]) * (x[i] - start[i])
+		}
+		return evaluateFSRS(scale(x), histories).logLoss + fsrsPrior/float64(reviews)*penalty
+	}
+
+	x := append([]float64(nil), start...)
+	best, bestLoss := append([]float64(nil), x...), objective(x)
+	m, v := make([]float64, n), make([]float64, n)
+	probe := make([]float64, n)
+	for t := 1; t <= iterations; t++ {
+		for i := range x {
+			// Central difference gradient
+			copy(probe, x)
+			probe[i] = x[i] + step
+			up := objective(probe)
+			probe[i] = x[i] - step
+			down := objective(probe)
+			g := (up - down) / (2 * step)
+
+			m[i] = beta1*m[i] + (1-beta1)*g
+			v[i] = beta2*v[i] + (1-beta2)*g*g
+			mHat := m[i] / (1 - math.Pow(beta1, float64(t)))
+			vHat := v[i] / (1 - math.Pow(beta2, float64(t)))
+			x[i] = min(max(x[i]-rate*mHat/(math.Sqrt(vHat)+1e-8), 0), 1)
+		}
+		if loss := objective(x); loss < bestLoss {
+			best, bestLoss = append(best[:0], x...), loss
+		}
+	}
+	return scale(best)
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// Grading thresholds. Answers are compared to words by similarity (1 - normalized edit
+// distance) and to definitions by how many key words the two share (see definitionCoverage).
+const (
+	exactMatch   = 0.95 // counts as the right answer
+	closeMatch   = 0.8  // right, with a typo
+	partialMatch = 0.5  // wrong, but close
+
+	// An answer is slow when it takes longer than slowAnswerBase plus slowAnswerPerChar for
+	// each character typed
+	slowAnswerBase    = 8 * time.Second
+	slowAnswerPerChar = 300 * time.Millisecond
+)
+
+// Ways an answer can match
+const (
+	matchExact      = "exact"
+	matchTypo       = "typo"
+	matchSynonym    = "synonym"
+	matchDefinition = "definition"
+	matchPartial    = "partial"
+	matchWrong      = "wrong"
+	matchBlank      = "blank"
+)
+
+// stopWords are ignored when comparing an answer with a definition
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true, "from": true,
+	"into": true, "onto": true, "are": true, "was": true, "were": true, "has": true, "have": true,
+	"not": true, "but": true, "its": true, "his": true, "her": true, "their": true, "your": true,
+	"you": true, "someone": true, "something": true, "one": true, "who": true, "which": true,
+}
+
+// gradeTarget is something an answer is compared with
+type gradeTarget struct {
+	text       string
+	definition bool // compared by key words rather than edit distance
+	synonym    bool // an accepted alternative to the expected answer
+}
+
+// gradeTargets lists what a card accepts: the word for cards that ask for it (and its
+// synonyms, except on spelling cards), synonyms for synonym cards, and definitions or
+// synonyms for recognition cards
+func gradeTargets(cardType string, entry *models.Word, notes *models.WordNotes) []gradeTarget {
+	var targets []gradeTarget
+	synonyms := func(accepted bool) {
+		for _, synonym := range entrySynonyms(entry) {
+			targets = append(targets, gradeTarget{text: synonym, synonym: accepted})
+		}
+	}
+
+	switch cardType {
+	case models.CardRecall, models.CardCloze:
+		targets = append(targets, gradeTarget{text: entry.Word})
+		synonyms(true)
+	case models.CardSpelling:
+		targets = append(targets, gradeTarget{text: entry.Word})
+	case models.CardSynonym:
+		synonyms(false)
+	default:
+		if notes != nil && notes.PreferredDefinition != "" {
+			targets = append(targets, gradeTarget{text: notes.PreferredDefinition, definition: true})
+		}
+		for _, meaning := range entry.Meanings {
+			for _, definition := range meaning.Definitions {
+				if definition.Definition != "" {
+					targets = append(targets, gradeTarget{text: definition.Definition, definition: true})
+				}
+			}
+		}
+		synonyms(true)
+	}
+	return targets
+}
+
+// normalizeAnswer lowercases text and reduces it to words separated by single spaces
+func normalizeAnswer(text string) string {
+	return strings.Join(answerWords(text), " ")
+}
+
+// answerWords splits text into lowercase words, dropping punctuation
+func answerWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '-'
+	})
+}
+
+// keyWords are an answer's words worth comparing with a definition, leaving out the headword
+// itself
+func keyWords(text, headword string) []string {
+	var words []string
+	for _, word := range answerWords(text) {
+		if len([]rune(word)) >= 3 && !stopWords[word] && !strings.EqualFold(word, headword) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// definitionCoverage scores how well an answer matches a definition, as the F0.5 score of the
+// share of the answer's key words the definition uses and the share of the definition's key
+// words the answer uses, each allowing a typo. Weighting the first keeps a shorter paraphrase
+// close, while an answer of one or two of the definition's words can't pass for all of it.
+func definitionCoverage(answer, definition, headword string) float64 {
+	answerKeys := keyWords(answer, headword)
+	definitionKeys := keyWords(definition, headword)
+	if len(answerKeys) == 0 || len(definitionKeys) == 0 {
+		return 0
+	}
+
+	precision := sharedKeyWords(answerKeys, answerWords(definition))
+	recall := sharedKeyWords(definitionKeys, answerWords(answer))
+	if precision == 0 || recall == 0 {
+		return 0
+	}
+	return 1.25 * precision * recall / (0.25*precision + recall)
+}
+
+// sharedKeyWords is the share of keys that are among words, allowing a typo
+func sharedKeyWords(keys, words []string) float64 {
+	found := 0
+	for _, key := range keys {
+		for _, word := range words {
+			if similarity(key, word) >= closeMatch {
+				found++
+				break
+			}
+		}
+	}
+	return float64(found) / float64(len(keys))
+}
+
+// gradeAnswer grades a typed answer to a card and maps it to a review quality: 5 for the
+// expected answer, 4 for one with a typo or an accepted synonym, one less for either if it
+// was slow, 2 for a near miss, 1 for a wrong answer and 0 for none. Spelling cards test the
+// spelling itself, so there a typo is a near miss. A responseTime of 0 means it wasn't
+// measured.
+func gradeAnswer(cardType string, entry *models.Word, notes *models.WordNotes, answer string, responseTime time.Duration) *models.AnswerGrade {
+	_, expected := cardFace(cardType, entry, notes)
+	grade := &models.AnswerGrade{
+		Answer:     answer,
+		Expected:   expected,
+		ResponseMS: responseTime.Milliseconds(),
+	}
+
+	normalized := normalizeAnswer(answer)
+	if normalized == "" {
+		grade.Match = matchBlank
+		grade.Explanation = fmt.Sprintf("No answer given; the answer is %q.", expected)
+		return grade
+	}
+
+	var best gradeTarget
+	for _, target := range gradeTargets(cardType, entry, notes) {
+		var score float64
+		if target.definition {
+			score = definitionCoverage(answer, target.text, entry.Word)
+		} else {
+			score = similarity(normalized, normalizeAnswer(target.text))
+		}
+		// Prefer the expected answer over a synonym that matches as well
+		if score > grade.Similarity || (score == grade.Similarity && best.synonym && !target.synonym) {
+			grade.Similarity = score
+			best = target
+		}
+	}
+	grade.MatchedWith = best.text
+
+	limit := slowAnswerBase + time.Duration(len([]rune(answer)))*slowAnswerPerChar
+	grade.Slow = responseTime > limit
+
+	switch {
+	case cardType == models.CardSpelling && grade.Similarity < 1 && grade.Similarity >= partialMatch:
+		grade.Match = matchPartial
+		grade.Quality = 2
+		grade.Explanation = fmt.Sprintf("Misspelled; the word is spelled %q.", best.text)
+	case grade.Similarity >= closeMatch && best.synonym:
+		grade.Match = matchSynonym
+		grade.Quality = 4
+		grade.Explanation = fmt.Sprintf("Accepted %q as a synonym; the expected answer is %q.", best.text, expected)
+	case grade.Similarity >= exactMatch && best.definition:
+		grade.Match = matchDefinition
+		grade.Quality = 5
+		grade.Explanation = fmt.Sprintf("Matches the definition %q.", best.text)
+	case grade.Similarity >= exactMatch:
+		grade.Match = matchExact
+		grade.Quality = 5
+		grade.Explanation = "Correct."
+	case grade.Similarity >= closeMatch && best.definition:
+		grade.Match = matchDefinition
+		grade.Quality = 4
+		grade.Explanation = fmt.Sprintf("Mostly matches the definition %q.", best.text)
+	case grade.Similarity >= closeMatch:
+		grade.Match = matchTypo
+		grade.Quality = 4
+		grade.Explanation = fmt.Sprintf("Correct apart from a typo; the answer is %q.", best.text)
+	case grade.Similarity >= partialMatch:
+		grade.Match = matchPartial
+		grade.Quality = 2
+		grade.Explanation = fmt.Sprintf("Close, but not right: the nearest answer is %q.", best.text)
+	default:
+		grade.Match = matchWrong
+		grade.Quality = 1
+		grade.Explanation = fmt.Sprintf("Incorrect; the answer is %q.", expected)
+	}
+
+	if grade.Slow && grade.Quality >= 4 {
+		grade.Quality--
+		grade.Explanation += fmt.Sprintf(" Answered slowly (%.1fs), so rated %d.", responseTime.Seconds(), grade.Quality)
+	}
+	return grade
+}
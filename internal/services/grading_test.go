@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/words-api/words/internal/models"
+)
+
+func TestGradeAnswer(t *testing.T) {
+	entry := &models.Word{
+		Word: "fleeting",
+		Meanings: []models.Meaning{{
+			PartOfSpeech: "adjective",
+			Definitions:  []models.Definition{{Definition: "lasting for a very short time"}},
+			Synonyms:     []string{"brief", "transient"},
+		}},
+	}
+
+	tests := []struct {
+		name         string
+		cardType     string
+		answer       string
+		responseTime time.Duration
+		match        string
+		quality      int
+	}{
+		{"definition", models.CardRecognition, "Lasting a very short time.", 0, matchDefinition, 5},
+		{"slow definition", models.CardRecognition, "lasting a very short time", time.Minute, matchDefinition, 4},
+		{"most of the definition", models.CardRecognition, "a short time", 0, matchDefinition, 4},
+		{"one word of the definition", models.CardRecognition, "very", 0, matchPartial, 2},
+		{"another word of the definition", models.CardRecognition, "time", 0, matchPartial, 2},
+		{"synonym for a definition", models.CardRecognition, "brief", 0, matchSynonym, 4},
+		{"wrong definition", models.CardRecognition, "a kind of fruit", 0, matchWrong, 1},
+		{"blank", models.CardRecognition, "  ", 0, matchBlank, 0},
+		{"word", models.CardRecall, "Fleeting", 0, matchExact, 5},
+		{"typo", models.CardRecall, "fleetng", 0, matchTypo, 4},
+		{"synonym for the word", models.CardRecall, "transient", 0, matchSynonym, 4},
+		{"spelling", models.CardSpelling, "fleeting", 0, matchExact, 5},
+		{"typo on a spelling card", models.CardSpelling, "fleetng", 0, matchPartial, 2},
+		{"synonym on a spelling card", models.CardSpelling, "transient", 0, matchWrong, 1},
+		{"synonym card", models.CardSynonym, "brief", 0, matchExact, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grade := gradeAnswer(tt.cardType, entry, nil, tt.answer, tt.responseTime)
+			if grade.Match != tt.match || grade.Quality != tt.quality {
+				t.Errorf("gradeAnswer(%q) = %s, quality %d (similarity %.2f); want %s, quality %d",
+					tt.answer, grade.Match, grade.Quality, grade.Similarity, tt.match, tt.quality)
+			}
+		})
+	}
+}
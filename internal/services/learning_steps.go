@@ -0,0 +1,135 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Steps for users who haven't configured their own, as stored in the users table: durations
+// separated by spaces
+const (
+	DefaultLearningSteps   = "1m 10m"
+	DefaultRelearningSteps = "10m"
+)
+
+// maxLearningSteps limits how many steps each list can have
+const maxLearningSteps = 10
+
+// LearningSteps are the short delays a word goes through before the scheduler's day-based
+// intervals take over: Learning for a newly added word, Relearning for one that was forgotten
+type LearningSteps struct {
+	Learning   []time.Duration
+	Relearning []time.Duration
+}
+
+// Next returns the state after a review graded quality (0-5) at now and when the word is due
+// again. Within the steps, a failed review goes back to the first step, 3 repeats the current
+// one, 4 moves to the next and 5 graduates the word straight away. A new word graduates with
+// its first scheduler review; a relearned word resumes the interval set when it was forgotten.
+func (steps LearningSteps) Next(scheduler Scheduler, state CardState, quality int, now time.Time) (CardState, time.Time) {
+	if state.Step == 0 {
+		state = scheduler.Next(state, quality, now)
+		if quality < 3 && len(steps.Relearning) > 0 {
+			state.Step, state.Relearning = 1, true
+			state.Status = StatusLearning
+			return state, now.Add(steps.Relearning[0])
+		}
+		return state, nextReviewDate(now, state.IntervalDays)
+	}
+
+	current := steps.Learning
+	if state.Relearning {
+		current = steps.Relearning
+	}
+	switch {
+	case quality < 3:
+		state.Step = 1
+	case quality == 4:
+		state.Step++
+	case quality == 5:
+		state.Step = len(current) + 1
+	}
+	if state.Step <= len(current) {
+		state.Status = StatusLearning
+		return state, now.Add(current[state.Step-1])
+	}
+
+	// Graduate; the steps may also have been shortened since the word entered them
+	relearning := state.Relearning
+	state.Step, state.Relearning = 0, false
+	if relearning {
+		state.Status = statusForInterval(state.IntervalDays)
+	} else {
+		state = scheduler.Next(state, quality, now)
+	}
+	return state, nextReviewDate(now, state.IntervalDays)
+}
+
+// FirstReview returns when a newly added word is first due and the step it starts at
+func (steps LearningSteps) FirstReview(now time.Time) (time.Time, int) {
+	if len(steps.Learning) == 0 {
+		return now, 0
+	}
+	return now.Add(steps.Learning[0]), 1
+}
+
+// ParseLearningSteps validates steps such as "1m", "10m", "1h" or "1d"
+func ParseLearningSteps(learning, relearning []string) (LearningSteps, error) {
+	var steps LearningSteps
+	var err error
+	if steps.Learning, err = parseSteps(learning); err != nil {
+		return steps, err
+	}
+	if steps.Relearning, err = parseSteps(relearning); err != nil {
+		return steps, err
+	}
+	return steps, nil
+}
+
+func parseSteps(steps []string) ([]time.Duration, error) {
+	if len(steps) > maxLearningSteps {
+		return nil, fmt.Errorf("too many learning steps")
+	}
+	durations := make([]time.Duration, 0, len(steps))
+	for _, step := range steps {
+		d, err := parseStep(strings.TrimSpace(step))
+		if err != nil || d < time.Minute || d > 7*24*time.Hour {
+			return nil, fmt.Errorf("learning steps must be durations from 1m to 7d")
+		}
+		durations = append(durations, d)
+	}
+	return durations, nil
+}
+
+// parseStep reads a Go duration, with "d" allowed for days
+func parseStep(step string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(step, "d"); ok {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err
+	}
+	return time.ParseDuration(step)
+}
+
+// formatSteps writes steps the way they are stored and shown
+func formatSteps(steps []time.Duration) []string {
+	formatted := make([]string, len(steps))
+	for i, d := range steps {
+		formatted[i] = formatStep(d)
+	}
+	return formatted
+}
+
+// formatStep writes a duration in the largest whole unit, e.g. "90m", "2h" or "1d"
+func formatStep(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
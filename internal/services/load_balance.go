@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// minFuzzInterval is the shortest interval, in days, that is fuzzed
+const minFuzzInterval = 3
+
+// fuzzRange returns the shortest and longest intervals, in days, a review due in intervalDays
+// may be moved to. As in Anki, the range widens by 15% of the interval up to a week, 10% up to
+// 20 days and 5% beyond, so words reviewed together drift apart instead of coming due on the
+// same day forever.
+func fuzzRange(intervalDays int) (int, int) {
+	if intervalDays < minFuzzInterval {
+		return intervalDays, intervalDays
+	}
+
+	days := float64(intervalDays)
+	delta := 1.0
+	delta += 0.15 * (min(days, 7) - 2.5)
+	delta += 0.10 * max(min(days, 20)-7, 0)
+	delta += 0.05 * max(days-20, 0)
+
+	low := max(int(math.Round(days-delta)), minFuzzInterval-1)
+	high := int(math.Round(days + delta))
+	return low, high
+}
+
+// leastLoaded picks the interval in [low, high] with the fewest words due that day, choosing
+// at random between equally loaded days
+func leastLoaded(low, high int, load func(days int) int) int {
+	best, bestLoad, ties := low, math.MaxInt, 0
+	for days := low; days <= high; days++ {
+		switch n := load(days); {
+		case n < bestLoad:
+			best, bestLoad, ties = days, n, 1
+		case n == bestLoad:
+			// Reservoir sampling keeps each tied day equally likely
+			ties++
+			if rand.IntN(ties) == 0 {
+				best = days
+			}
+		}
+	}
+	return best
+}
+
+// balancedReviewDate returns when a word reviewed at now with an interval of intervalDays is due
+// again: on the least-loaded day of the interval's fuzz range, counting the words the user
+// already has due on each day
+func (s *ReviewService) balancedReviewDate(userID, userWordID int64, now time.Time, intervalDays int) (time.Time, error) {
+	low, high := fuzzRange(intervalDays)
+	if low == high {
+		return nextReviewDate(now, intervalDays), nil
+	}
+
+	dayOf := func(days int) string {
+		return nextReviewDate(now, days).UTC().Format("2006-01-02")
+	}
+	rows, err := s.db.Query(`
+		SELECT date(next_review_date), COUNT(*)
+		FROM user_words
+		WHERE user_id = ? AND id != ? AND date(next_review_date) BETWEEN ? AND ?
+		GROUP BY date(next_review_date)
+	`, userID, userWordID, dayOf(low), dayOf(high))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to count due words: %w", err)
+	}
+	defer rows.Close()
+
+	histogram := make(map[string]int)
+	for rows.Next() {
+		var day string
+		var count int
+		if err := rows.Scan(&day, &count); err != nil {
+			return time.Time{}, fmt.Errorf("failed to count due words: %w", err)
+		}
+		histogram[day] = count
+	}
+	if err := rows.Err(); err != nil {
+		return time.Time{}, fmt.Errorf("failed to count due words: %w", err)
+	}
+
+	days := leastLoaded(low, high, func(days int) int { return histogram[dayOf(days)] })
+	return nextReviewDate(now, days), nil
+}
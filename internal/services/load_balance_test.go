@@ -0,0 +1,55 @@
+package services
+
+import "testing"
+
+func TestFuzzRange(t *testing.T) {
+	tests := []struct {
+		interval, low, high int
+	}{
+		{1, 1, 1},
+		{2, 2, 2},
+		{3, 2, 4},
+		{7, 5, 9},
+		{10, 8, 12},
+		{30, 27, 33},
+		{100, 93, 107},
+	}
+
+	for _, tt := range tests {
+		low, high := fuzzRange(tt.interval)
+		if low != tt.low || high != tt.high {
+			t.Errorf("fuzzRange(%d) = %d, %d; want %d, %d", tt.interval, low, high, tt.low, tt.high)
+		}
+	}
+}
+
+func TestLeastLoaded(t *testing.T) {
+	tests := []struct {
+		name      string
+		low, high int
+		load      map[int]int
+		want      []int // the days that may be picked
+	}{
+		{"single day", 5, 5, map[int]int{5: 9}, []int{5}},
+		{"fewest words due", 5, 9, map[int]int{5: 4, 6: 3, 7: 1, 8: 2, 9: 5}, []int{7}},
+		{"empty days first", 5, 7, map[int]int{5: 2, 7: 1}, []int{6}},
+		{"ties", 5, 9, map[int]int{5: 3, 6: 1, 7: 2, 8: 1, 9: 1}, []int{6, 8, 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picked := make(map[int]bool)
+			for range 200 {
+				picked[leastLoaded(tt.low, tt.high, func(days int) int { return tt.load[days] })] = true
+			}
+			if len(picked) != len(tt.want) {
+				t.Errorf("picked %v, want each of %v", picked, tt.want)
+			}
+			for _, day := range tt.want {
+				if !picked[day] {
+					t.Errorf("never picked day %d of %v", day, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,507 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// ModerationService handles user-contributed dictionary changes and their review
+type ModerationService struct {
+	db          *sql.DB
+	userService *UserService
+	wordService *WordService
+}
+
+// NewModerationService creates a new moderation service
+func NewModerationService(db *sql.DB) *ModerationService {
+	return &ModerationService{
+		db:          db,
+		userService: NewUserService(db),
+		wordService: NewWordService(db),
+	}
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+const pendingChangeColumns = `
+	pc.id, pc.user_id, u.username, pc.word_id, w.word, pc.change_type, pc.definition_id,
+	pc.payload, pc.status, pc.created_at, pc.reviewed_by, pc.reviewed_at, pc.review_note
+`
+
+const pendingChangeJoins = `
+	FROM pending_changes pc
+	JOIN users u ON pc.user_id = u.id
+	JOIN words w ON pc.word_id = w.id
+`
+
+// ProposeChange queues a user's proposed change to a word for moderation
+func (s *ModerationService) ProposeChange(username, wordStr string, request models.ProposeChangeRequest) (*models.PendingChange, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	word, err := s.wordService.GetWord(wordStr)
+	if err != nil {
+		return nil, fmt.Errorf("word not found: %w", err)
+	}
+
+	proposal := normalizeProposal(request.ChangeProposal)
+	if err := s.validateProposal(word.ID, request.ChangeType, request.DefinitionID, proposal); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(proposal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode proposal: %w", err)
+	}
+
+	var definitionID interface{}
+	if request.DefinitionID > 0 {
+		definitionID = request.DefinitionID
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO pending_changes (user_id, word_id, change_type, definition_id, payload, status, created_at)
+		VALUES (?, ?, ?, ?, ?, 'pending', ?)
+	`, user.ID, word.ID, request.ChangeType, definitionID, string(payload), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create change: %w", err)
+	}
+
+	changeID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change ID: %w", err)
+	}
+
+	if err := insertAudit(tx, changeID, user.ID, "proposed", "", 0, "", string(payload)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetChange(changeID)
+}
+
+// GetUserChanges retrieves all changes proposed by a user, newest first
+func (s *ModerationService) GetUserChanges(username string) ([]models.PendingChange, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.queryChanges(`WHERE pc.user_id = ? ORDER BY pc.created_at DESC`, user.ID)
+}
+
+// ListChanges retrieves changes in the moderation queue, optionally filtered by status.
+// Pending changes are listed oldest first so they are reviewed in order.
+func (s *ModerationService) ListChanges(status string) ([]models.PendingChange, error) {
+	if status == "" {
+		return s.queryChanges(`ORDER BY pc.created_at DESC`)
+	}
+	if status != "pending" && status != "approved" && status != "rejected" {
+		return nil, fmt.Errorf("invalid status")
+	}
+
+	order := "DESC"
+	if status == "pending" {
+		order = "ASC"
+	}
+	return s.queryChanges(`WHERE pc.status = ? ORDER BY pc.created_at `+order, status)
+}
+
+// GetChange retrieves a single change with its audit trail
+func (s *ModerationService) GetChange(id int64) (*models.PendingChange, error) {
+	change, err := scanPendingChange(s.db.QueryRow(`SELECT `+pendingChangeColumns+pendingChangeJoins+` WHERE pc.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("change not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, change_id, actor_id, action, table_name, row_id, old_value, new_value, created_at
+		FROM change_audit WHERE change_id = ? ORDER BY id ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit trail: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a models.ChangeAudit
+		var tableName, oldValue, newValue sql.NullString
+		var rowID sql.NullInt64
+		if err := rows.Scan(&a.ID, &a.ChangeID, &a.ActorID, &a.Action, &tableName, &rowID,
+			&oldValue, &newValue, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		a.TableName = tableName.String
+		a.RowID = rowID.Int64
+		a.OldValue = oldValue.String
+		a.NewValue = newValue.String
+		change.Audit = append(change.Audit, a)
+	}
+
+	return change, nil
+}
+
+// ApproveChange applies a pending change to the dictionary and records each modification
+func (s *ModerationService) ApproveChange(id int64, reviewer *models.User, note string) (*models.PendingChange, error) {
+	change, err := s.GetChange(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := markReviewed(tx, id, "approved", reviewer.ID, note); err != nil {
+		return nil, err
+	}
+
+	if err := applyChange(tx, change, reviewer.ID); err != nil {
+		return nil, err
+	}
+
+	if err := insertAudit(tx, id, reviewer.ID, "approved", "", 0, "", note); err != nil {
+		return nil, err
+	}
+
+	// Bump the entry's version so cached copies are revalidated
+	_, err = tx.Exec(`UPDATE words SET updated_at = ? WHERE id = ?`, time.Now(), change.WordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update word: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetChange(id)
+}
+
+// RejectChange closes a pending change without applying it
+func (s *ModerationService) RejectChange(id int64, reviewer *models.User, note string) (*models.PendingChange, error) {
+	if _, err := s.GetChange(id); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := markReviewed(tx, id, "rejected", reviewer.ID, note); err != nil {
+		return nil, err
+	}
+
+	if err := insertAudit(tx, id, reviewer.ID, "rejected", "", 0, "", note); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetChange(id)
+}
+
+// validateProposal checks that a proposal has the fields its change type needs
+func (s *ModerationService) validateProposal(wordID int64, changeType string, definitionID int64, p models.ChangeProposal) error {
+	switch changeType {
+	case models.ChangeAddDefinition:
+		if p.PartOfSpeech == "" {
+			return fmt.Errorf("part of speech is required")
+		}
+		if p.Definition == "" {
+			return fmt.Errorf("definition is required")
+		}
+		return nil
+	case models.ChangeEditDefinition:
+		if p.Definition == "" && p.Example == "" {
+			return fmt.Errorf("definition or example is required")
+		}
+	case models.ChangeAddExample:
+		if p.Example == "" {
+			return fmt.Errorf("example is required")
+		}
+	case models.ChangeAddSynonyms:
+		if len(p.Synonyms) == 0 {
+			return fmt.Errorf("at least one synonym is required")
+		}
+	default:
+		return fmt.Errorf("invalid change type")
+	}
+
+	// The remaining change types target an existing definition of this word
+	var ownerID int64
+	err := s.db.QueryRow(`
+		SELECT m.word_id FROM definitions d JOIN meanings m ON d.meaning_id = m.id WHERE d.id = ?
+	`, definitionID).Scan(&ownerID)
+	if err == sql.ErrNoRows || (err == nil && ownerID != wordID) {
+		return fmt.Errorf("definition not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get definition: %w", err)
+	}
+
+	return nil
+}
+
+// queryChanges runs a pending_changes query with the given filter and ordering clause
+func (s *ModerationService) queryChanges(clause string, args ...interface{}) ([]models.PendingChange, error) {
+	rows, err := s.db.Query(`SELECT `+pendingChangeColumns+pendingChangeJoins+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.PendingChange
+	for rows.Next() {
+		change, err := scanPendingChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan change: %w", err)
+		}
+		changes = append(changes, *change)
+	}
+
+	return changes, nil
+}
+
+func scanPendingChange(row rowScanner) (*models.PendingChange, error) {
+	c := &models.PendingChange{}
+	var definitionID, reviewedBy sql.NullInt64
+	var reviewedAt sql.NullTime
+	var reviewNote sql.NullString
+	var payload string
+
+	err := row.Scan(&c.ID, &c.UserID, &c.Username, &c.WordID, &c.Word, &c.ChangeType, &definitionID,
+		&payload, &c.Status, &c.CreatedAt, &reviewedBy, &reviewedAt, &reviewNote)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(payload), &c.Proposal); err != nil {
+		return nil, fmt.Errorf("failed to decode proposal: %w", err)
+	}
+	c.DefinitionID = definitionID.Int64
+	c.ReviewedBy = reviewedBy.Int64
+	if reviewedAt.Valid {
+		c.ReviewedAt = &reviewedAt.Time
+	}
+	c.ReviewNote = reviewNote.String
+
+	return c, nil
+}
+
+// markReviewed closes a change, failing if another moderator got to it first
+func markReviewed(tx *sql.Tx, id int64, status string, reviewerID int64, note string) error {
+	result, err := tx.Exec(`
+		UPDATE pending_changes
+		SET status = ?, reviewed_by = ?, reviewed_at = ?, review_note = ?
+		WHERE id = ? AND status = 'pending'
+	`, status, reviewerID, time.Now(), note, id)
+	if err != nil {
+		return fmt.Errorf("failed to update change: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update change: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("change already reviewed")
+	}
+
+	return nil
+}
+
+// applyChange writes an approved change to the dictionary tables
+func applyChange(tx *sql.Tx, change *models.PendingChange, actorID int64) error {
+	p := change.Proposal
+
+	switch change.ChangeType {
+	case models.ChangeAddDefinition:
+		// Attach to the word's existing meaning for this part of speech, or start a new one
+		var meaningID int64
+		err := tx.QueryRow(`
+			SELECT id FROM meanings WHERE word_id = ? AND part_of_speech = ? ORDER BY id LIMIT 1
+		`, change.WordID, p.PartOfSpeech).Scan(&meaningID)
+		if err == sql.ErrNoRows {
+			result, err := tx.Exec(`
+				INSERT INTO meanings (word_id, part_of_speech) VALUES (?, ?)
+			`, change.WordID, p.PartOfSpeech)
+			if err != nil {
+				return fmt.Errorf("failed to insert meaning: %w", err)
+			}
+			if meaningID, err = result.LastInsertId(); err != nil {
+				return fmt.Errorf("failed to get meaning ID: %w", err)
+			}
+			if err := insertAudit(tx, change.ID, actorID, "applied", "meanings", meaningID, "", p.PartOfSpeech); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return fmt.Errorf("failed to get meaning: %w", err)
+		}
+
+		result, err := tx.Exec(`
+			INSERT INTO definitions (meaning_id, definition, example) VALUES (?, ?, ?)
+		`, meaningID, p.Definition, p.Example)
+		if err != nil {
+			return fmt.Errorf("failed to insert definition: %w", err)
+		}
+		definitionID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get definition ID: %w", err)
+		}
+		if err := insertAudit(tx, change.ID, actorID, "applied", "definitions", definitionID, "", p.Definition); err != nil {
+			return err
+		}
+
+		return insertSynonyms(tx, change.ID, actorID, definitionID, p.Synonyms)
+
+	case models.ChangeEditDefinition, models.ChangeAddExample:
+		var oldDefinition string
+		var oldExample sql.NullString
+		err := tx.QueryRow(`
+			SELECT definition, example FROM definitions WHERE id = ?
+		`, change.DefinitionID).Scan(&oldDefinition, &oldExample)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("definition not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get definition: %w", err)
+		}
+
+		if p.Definition != "" && p.Definition != oldDefinition {
+			if _, err := tx.Exec(`UPDATE definitions SET definition = ? WHERE id = ?`, p.Definition, change.DefinitionID); err != nil {
+				return fmt.Errorf("failed to update definition: %w", err)
+			}
+			if err := insertAudit(tx, change.ID, actorID, "applied", "definitions", change.DefinitionID, oldDefinition, p.Definition); err != nil {
+				return err
+			}
+		}
+
+		if p.Example != "" && p.Example != oldExample.String {
+			if _, err := tx.Exec(`UPDATE definitions SET example = ? WHERE id = ?`, p.Example, change.DefinitionID); err != nil {
+				return fmt.Errorf("failed to update example: %w", err)
+			}
+			if err := insertAudit(tx, change.ID, actorID, "applied", "definitions.example", change.DefinitionID, oldExample.String, p.Example); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case models.ChangeAddSynonyms:
+		return insertSynonyms(tx, change.ID, actorID, change.DefinitionID, p.Synonyms)
+	}
+
+	return fmt.Errorf("invalid change type")
+}
+
+// insertSynonyms adds definition-level synonyms that aren't already present
+func insertSynonyms(tx *sql.Tx, changeID, actorID, definitionID int64, synonyms []string) error {
+	rows, err := tx.Query(`SELECT synonym FROM synonyms WHERE definition_id = ?`, definitionID)
+	if err != nil {
+		return fmt.Errorf("failed to get synonyms: %w", err)
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var syn string
+		if err := rows.Scan(&syn); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan synonym: %w", err)
+		}
+		existing[strings.ToLower(syn)] = true
+	}
+	rows.Close()
+
+	for _, syn := range synonyms {
+		if existing[strings.ToLower(syn)] {
+			continue
+		}
+		result, err := tx.Exec(`INSERT INTO synonyms (definition_id, synonym) VALUES (?, ?)`, definitionID, syn)
+		if err != nil {
+			return fmt.Errorf("failed to insert synonym: %w", err)
+		}
+		synonymID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get synonym ID: %w", err)
+		}
+		if err := insertAudit(tx, changeID, actorID, "applied", "synonyms", synonymID, "", syn); err != nil {
+			return err
+		}
+		existing[strings.ToLower(syn)] = true
+	}
+
+	return nil
+}
+
+// insertAudit appends an entry to a change's audit trail
+func insertAudit(tx *sql.Tx, changeID, actorID int64, action, tableName string, rowID int64, oldValue, newValue string) error {
+	var table, row, before, after interface{}
+	if tableName != "" {
+		table = tableName
+		row = rowID
+	}
+	if oldValue != "" {
+		before = oldValue
+	}
+	if newValue != "" {
+		after = newValue
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO change_audit (change_id, actor_id, action, table_name, row_id, old_value, new_value, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, changeID, actorID, action, table, row, before, after, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeProposal trims whitespace and drops empty or duplicate synonyms
+func normalizeProposal(p models.ChangeProposal) models.ChangeProposal {
+	p.PartOfSpeech = strings.ToLower(strings.TrimSpace(p.PartOfSpeech))
+	p.Definition = strings.TrimSpace(p.Definition)
+	p.Example = strings.TrimSpace(p.Example)
+
+	seen := make(map[string]bool)
+	var synonyms []string
+	for _, syn := range p.Synonyms {
+		syn = strings.TrimSpace(syn)
+		if syn == "" || seen[strings.ToLower(syn)] {
+			continue
+		}
+		seen[strings.ToLower(syn)] = true
+		synonyms = append(synonyms, syn)
+	}
+	p.Synonyms = synonyms
+
+	return p
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/words-api/words/internal/models"
+)
+
+const (
+	// quizOptions is the number of options each question offers
+	quizOptions = 4
+	// maxSynonymDistractors caps the distractors taken from words sharing synonyms, which are
+	// the hardest to tell apart
+	maxSynonymDistractors = 2
+	// frequencyPool is how many words nearest in frequency distractors are drawn from
+	frequencyPool = 20
+	// quizSlowAnswer is how long an answer can take before a correct one is rated 3, not 4
+	quizSlowAnswer = 15 * time.Second
+)
+
+// QuizService builds multiple-choice quizzes from a user's studied words and reviews their
+// recognition cards with the answers
+type QuizService struct {
+	db                *sql.DB
+	userService       *UserService
+	reviewService     *ReviewService
+	vocabularyService *VocabularyService
+}
+
+// NewQuizService creates a new quiz service
+func NewQuizService(db *sql.DB) *QuizService {
+	return &QuizService{
+		db:                db,
+		userService:       NewUserService(db),
+		reviewService:     NewReviewService(db),
+		vocabularyService: NewVocabularyService(db),
+	}
+}
+
+// quizDefinition is the definition a question asks for: the user's preferred one, else the
+// word's first
+func quizDefinition(entry *models.Word, notes *models.WordNotes) (models.QuizOption, string, bool) {
+	var first *models.QuizOption
+	var firstPOS string
+	for _, meaning := range entry.Meanings {
+		for _, definition := range meaning.Definitions {
+			if definition.Definition == "" {
+				continue
+			}
+			option := models.QuizOption{DefinitionID: definition.ID, Definition: definition.Definition}
+			if notes != nil && definition.ID == notes.PreferredDefinitionID {
+				return option, meaning.PartOfSpeech, true
+			}
+			if first == nil {
+				first, firstPOS = &option, meaning.PartOfSpeech
+			}
+		}
+	}
+	if first == nil {
+		return models.QuizOption{}, "", false
+	}
+	return *first, firstPOS, true
+}
+
+// distractor is a definition of another word offered as a wrong answer
+type distractor struct {
+	wordID int64
+	option models.QuizOption
+}
+
+// BuildQuiz builds up to count questions on words the user studies with a recognition card,
+// chosen and laid out by seed
+func (s *QuizService) BuildQuiz(username string, count int, seed int64) (*models.Quiz, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT w.word FROM user_words uw
+		JOIN words w ON uw.word_id = w.id
+		WHERE uw.user_id = ? AND uw.card_type = ?
+		ORDER BY w.word
+	`, user.ID, models.CardRecognition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get studied words: %w", err)
+	}
+	var words []string
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan studied word: %w", err)
+		}
+		words = append(words, word)
+	}
+	rows.Close()
+	if len(words) == 0 {
+		return nil, fmt.Errorf("no words to quiz")
+	}
+
+	notes, err := s.vocabularyService.loadNotes(user.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	rng.Shuffle(len(words), func(i, j int) { words[i], words[j] = words[j], words[i] })
+
+	quiz := &models.Quiz{Seed: seed, Questions: []models.QuizQuestion{}}
+	for _, word := range words {
+		if len(quiz.Questions) == count {
+			break
+		}
+		entry, err := s.vocabularyService.wordService.GetWord(word)
+		if err != nil {
+			return nil, fmt.Errorf("word not found: %w", err)
+		}
+		answer, partOfSpeech, ok := quizDefinition(entry, notes[entry.ID])
+		if !ok {
+			continue
+		}
+
+		distractors, err := s.distractors(entry, answer, partOfSpeech, rng)
+		if err != nil {
+			return nil, err
+		}
+		if len(distractors) == 0 {
+			continue
+		}
+
+		options := []models.QuizOption{answer}
+		for _, d := range distractors {
+			options = append(options, d.option)
+		}
+		rng.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+
+		quiz.Questions = append(quiz.Questions, models.QuizQuestion{
+			Word:         entry.Word,
+			PartOfSpeech: partOfSpeech,
+			Options:      options,
+		})
+	}
+
+	quiz.Count = len(quiz.Questions)
+	return quiz, nil
+}
+
+// distractors picks definitions of other words with the answer's part of speech: first from
+// words sharing the most synonyms with the entry, then from words of similar frequency. Words
+// listed as the entry's synonyms are left out, since their definitions could also be right.
+func (s *QuizService) distractors(entry *models.Word, answer models.QuizOption, partOfSpeech string, rng *rand.Rand) ([]distractor, error) {
+	synonyms := collectSynonyms(entry)
+	var picked []distractor
+	usedWords := map[int64]bool{entry.ID: true}
+	usedDefinitions := map[string]bool{strings.ToLower(answer.Definition): true}
+	take := func(candidates []distractor, limit int) {
+		for _, candidate := range candidates {
+			if len(picked) == limit {
+				return
+			}
+			key := strings.ToLower(candidate.option.Definition)
+			if usedWords[candidate.wordID] || usedDefinitions[key] {
+				continue
+			}
+			usedWords[candidate.wordID] = true
+			usedDefinitions[key] = true
+			picked = append(picked, candidate)
+		}
+	}
+
+	shared, err := s.sharedSynonymDistractors(entry, synonyms, partOfSpeech, rng)
+	if err != nil {
+		return nil, err
+	}
+	take(shared, maxSynonymDistractors)
+
+	// Fall back to any part of speech if too few words share this one
+	for _, pos := range []string{partOfSpeech, ""} {
+		if len(picked) == quizOptions-1 {
+			break
+		}
+		similar, err := s.frequencyDistractors(entry, synonyms, pos, rng)
+		if err != nil {
+			return nil, err
+		}
+		take(similar, quizOptions-1)
+	}
+	return picked, nil
+}
+
+// sharedSynonymDistractors returns a definition for each word that shares synonyms with the
+// entry, most shared first
+func (s *QuizService) sharedSynonymDistractors(entry *models.Word, synonyms map[string]bool, partOfSpeech string, rng *rand.Rand) ([]distractor, error) {
+	if len(synonyms) == 0 {
+		return nil, nil
+	}
+	args := []interface{}{entry.ID}
+	for synonym := range synonyms {
+		args = append(args, synonym)
+	}
+	args = append(args, partOfSpeech)
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(synonyms)), ", ")
+
+	rows, err := s.db.Query(`
+		SELECT m.word_id, w.word, d.id, d.definition, shared.count
+		FROM (
+			SELECT sm.word_id, COUNT(DISTINCT LOWER(sy.synonym)) AS count
+			FROM synonyms sy
+			LEFT JOIN definitions sd ON sy.definition_id = sd.id
+			JOIN meanings sm ON sm.id = COALESCE(sy.meaning_id, sd.meaning_id)
+			WHERE sm.word_id != ? AND LOWER(sy.synonym) IN (`+placeholders+`)
+			GROUP BY sm.word_id
+		) shared
+		JOIN words w ON w.id = shared.word_id
+		JOIN meanings m ON m.word_id = shared.word_id AND m.part_of_speech = ?
+		JOIN definitions d ON d.meaning_id = m.id AND d.definition != ''
+		ORDER BY m.word_id, m.id, d.id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find distractors: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []distractor
+	sharedCounts := make(map[int64]int)
+	for rows.Next() {
+		var d distractor
+		var word string
+		var count int
+		if err := rows.Scan(&d.wordID, &word, &d.option.DefinitionID, &d.option.Definition, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan distractor: %w", err)
+		}
+		if _, seen := sharedCounts[d.wordID]; seen || synonyms[strings.ToLower(word)] {
+			continue
+		}
+		sharedCounts[d.wordID] = count
+		candidates = append(candidates, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find distractors: %w", err)
+	}
+
+	rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return sharedCounts[candidates[i].wordID] > sharedCounts[candidates[j].wordID]
+	})
+	return candidates, nil
+}
+
+// frequencyDistractors returns a definition for each of the words nearest the entry in
+// frequency, in random order. Frequency is the number of corpus examples mined for a word;
+// ties are broken by a seeded order so every seed draws different words.
+func (s *QuizService) frequencyDistractors(entry *models.Word, synonyms map[string]bool, partOfSpeech string, rng *rand.Rand) ([]distractor, error) {
+	var frequency int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM corpus_examples WHERE word_id = ?`, entry.ID).Scan(&frequency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get word frequency: %w", err)
+	}
+
+	filter := ""
+	args := []interface{}{entry.ID}
+	if partOfSpeech != "" {
+		filter = " AND m.part_of_speech = ?"
+		args = append(args, partOfSpeech)
+	}
+	args = append(args, frequency, rng.Int64N(1<<30)*2+1, frequencyPool*quizOptions)
+
+	rows, err := s.db.Query(`
+		SELECT w.id, w.word, d.id, d.definition
+		FROM words w
+		JOIN meanings m ON m.word_id = w.id
+		JOIN definitions d ON d.meaning_id = m.id AND d.definition != ''
+		WHERE w.id != ?`+filter+`
+		ORDER BY ABS((SELECT COUNT(*) FROM corpus_examples ce WHERE ce.word_id = w.id) - ?),
+		         (w.id * ?) % 2147483647, m.id, d.id
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find distractors: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []distractor
+	seen := make(map[int64]bool)
+	for rows.Next() {
+		var d distractor
+		var word string
+		if err := rows.Scan(&d.wordID, &word, &d.option.DefinitionID, &d.option.Definition); err != nil {
+			return nil, fmt.Errorf("failed to scan distractor: %w", err)
+		}
+		if seen[d.wordID] || synonyms[strings.ToLower(word)] || len(candidates) == frequencyPool {
+			continue
+		}
+		seen[d.wordID] = true
+		candidates = append(candidates, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find distractors: %w", err)
+	}
+
+	rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	return candidates, nil
+}
+
+// Answer checks a quiz answer and reviews the word's recognition card with it: 4 if correct
+// (3 if slow) and 1 if not. Any of the word's definitions counts as correct.
+func (s *QuizService) Answer(username string, request models.QuizAnswerRequest) (*models.QuizResult, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.vocabularyService.wordService.GetWord(strings.ToLower(strings.TrimSpace(request.Word)))
+	if err != nil {
+		return nil, fmt.Errorf("word not found: %w", err)
+	}
+
+	var wordID int64
+	err = s.db.QueryRow(`
+		SELECT m.word_id FROM definitions d JOIN meanings m ON d.meaning_id = m.id WHERE d.id = ?
+	`, request.DefinitionID).Scan(&wordID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("definition not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get definition: %w", err)
+	}
+
+	notes, err := s.vocabularyService.loadNotes(user.ID, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	answer, _, _ := quizDefinition(entry, notes[entry.ID])
+
+	result := &models.QuizResult{Correct: wordID == entry.ID, Quality: 1, Answer: answer}
+	if result.Correct {
+		result.Quality = 4
+		if time.Duration(request.ResponseMS)*time.Millisecond > quizSlowAnswer {
+			result.Quality = 3
+		}
+	}
+
+	result.UserWord, err = s.reviewService.SubmitReview(username, entry.Word, models.CardRecognition, result.Quality)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/words-api/words/internal/database"
+	"github.com/words-api/words/internal/models"
+)
+
+func TestBuildQuizIsReproducible(t *testing.T) {
+	db, err := database.InitDB("file:" + t.Name() + "?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	entries := []struct {
+		word, partOfSpeech, definition string
+		synonyms                       []string
+		examples                       int
+	}{
+		{"brief", "adjective", "lasting a short time", []string{"short", "quick"}, 4},
+		{"fleeting", "adjective", "passing swiftly and soon forgotten", []string{"short", "transient"}, 1},
+		{"enormous", "adjective", "very large in size or amount", []string{"huge", "vast"}, 3},
+		{"tiny", "adjective", "extremely small", []string{"small", "minute"}, 5},
+		{"vast", "adjective", "of very great extent or quantity", []string{"huge", "immense"}, 2},
+		{"quick", "adjective", "moving fast or doing something in a short time", []string{"fast", "swift"}, 6},
+		{"ancient", "adjective", "belonging to the very distant past", []string{"old"}, 0},
+		{"river", "noun", "a large natural stream of water", nil, 2},
+		{"hill", "noun", "a naturally raised area of land", []string{"mound"}, 1},
+	}
+
+	words := NewWordService(db)
+	for _, entry := range entries {
+		word := &models.Word{
+			Word: entry.word,
+			Meanings: []models.Meaning{{
+				PartOfSpeech: entry.partOfSpeech,
+				Definitions:  []models.Definition{{Definition: entry.definition}},
+				Synonyms:     entry.synonyms,
+			}},
+		}
+		if err := words.saveToDB(word); err != nil {
+			t.Fatal(err)
+		}
+		for i := range entry.examples {
+			_, err := db.Exec(`
+				INSERT INTO corpus_examples (word_id, sentence, source, position, length, score)
+				SELECT id, ?, 'test', 0, 10, 1 FROM words WHERE word = ?
+			`, fmt.Sprintf("%s example %d", entry.word, i), entry.word)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if _, err := NewUserService(db).CreateUser("reader"); err != nil {
+		t.Fatal(err)
+	}
+	vocabulary := NewVocabularyService(db)
+	for _, entry := range entries[:6] {
+		if _, err := vocabulary.AddWord("reader", entry.word, []string{models.CardRecognition}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	service := NewQuizService(db)
+	first, err := service.BuildQuiz("reader", 5, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.BuildQuiz("reader", 5, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(first.Questions) != 5 {
+		t.Fatalf("got %d questions, want 5", len(first.Questions))
+	}
+	for _, question := range first.Questions {
+		if len(question.Options) != quizOptions {
+			t.Errorf("question on %q has %d options, want %d", question.Word, len(question.Options), quizOptions)
+		}
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("the same seed built different quizzes:\n%+v\n%+v", first, second)
+	}
+}
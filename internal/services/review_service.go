@@ -2,8 +2,9 @@ package services
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"math"
+	"slices"
 	"time"
 
 	"github.com/words-api/words/internal/models"
@@ -14,6 +15,7 @@ type ReviewService struct {
 	db               *sql.DB
 	userService      *UserService
 	vocabularyService *VocabularyService
+	compareService    *CompareService
 }
 
 // NewReviewService creates a new review service
@@ -22,164 +24,441 @@ func NewReviewService(db *sql.DB) *ReviewService {
 		db:               db,
 		userService:      NewUserService(db),
 		vocabularyService: NewVocabularyService(db),
+		compareService:    NewCompareService(db),
 	}
 }
 
-// GetDueWords retrieves words that are due for review
-func (s *ReviewService) GetDueWords(username string) ([]models.UserWord, error) {
+// GetDueWords retrieves words that are due for review, within the user's daily limits on new
+// words and reviews, and what is left of those limits
+func (s *ReviewService) GetDueWords(username string) ([]models.UserWord, *models.ReviewQuota, error) {
 	// Get user
 	user, err := s.userService.GetUser(username)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	quota, err := s.reviewQuota(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Words reviewed before: those in their learning steps are always due, the others count
+	// against the review limit
+	reviewed, err := s.queryDueWords(user.ID, true, -1)
+	if err != nil {
+		return nil, nil, err
+	}
+	var dueWords []models.UserWord
+	reviews := 0
+	for _, uw := range reviewed {
+		if uw.LearningStep == 0 {
+			if reviews == quota.ReviewsRemaining {
+				continue
+			}
+			reviews++
+		}
+		dueWords = append(dueWords, uw)
+	}
+
+	// Then new words, up to the new word limit
+	newWords, err := s.queryDueWords(user.ID, false, quota.NewRemaining)
+	if err != nil {
+		return nil, nil, err
 	}
+	dueWords = append(dueWords, newWords...)
 
-	// Query words due for review (next_review_date <= now)
+	// Flag words that are easily confused with another word in the user's list
+	partners, err := s.compareService.GetConfusablesInList(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range dueWords {
+		dueWords[i].ConfusableWith = partners[dueWords[i].WordID]
+	}
+
+	if err := s.vocabularyService.attachNotes(user.ID, dueWords); err != nil {
+		return nil, nil, err
+	}
+
+	return dueWords, quota, nil
+}
+
+// queryDueWords returns a user's due words, earliest first, that have or haven't been reviewed
+// before, up to limit unless it is negative. datetime() converts the stored times to UTC, so
+// words in learning steps come due on the minute.
+func (s *ReviewService) queryDueWords(userID int64, reviewedBefore bool, limit int) ([]models.UserWord, error) {
+	exists := "NOT EXISTS"
+	if reviewedBefore {
+		exists = "EXISTS"
+	}
 	rows, err := s.db.Query(`
-		SELECT uw.id, uw.user_id, uw.word_id, w.word, uw.added_at, uw.status,
-		       uw.next_review_date, uw.ease_factor, uw.interval_days
+		SELECT `+userWordColumns+`
 		FROM user_words uw
 		JOIN words w ON uw.word_id = w.id
-		WHERE uw.user_id = ? AND uw.next_review_date <= datetime('now')
+		WHERE uw.user_id = ? AND datetime(uw.next_review_date) <= datetime('now')
+		  AND `+exists+` (
+			SELECT 1 FROM review_history rh
+			WHERE rh.user_id = uw.user_id AND rh.word_id = uw.word_id AND rh.card_type = uw.card_type
+		  )
 		ORDER BY uw.next_review_date ASC
-	`, user.ID)
-
+		LIMIT ?
+	`, userID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get due words: %w", err)
 	}
 	defer rows.Close()
 
-	var dueWords []models.UserWord
+	var words []models.UserWord
 	for rows.Next() {
 		var uw models.UserWord
-		err := rows.Scan(&uw.ID, &uw.UserID, &uw.WordID, &uw.Word, &uw.AddedAt,
-			&uw.Status, &uw.NextReviewDate, &uw.EaseFactor, &uw.IntervalDays)
-		if err != nil {
+		if err := scanUserWord(rows, &uw); err != nil {
 			return nil, fmt.Errorf("failed to scan due word: %w", err)
 		}
-		dueWords = append(dueWords, uw)
+		words = append(words, uw)
+	}
+	return words, rows.Err()
+}
+
+// reviewQuota counts the cards a user has reviewed today (UTC) against their daily limits. A
+// card counts as new on the day of its first review.
+func (s *ReviewService) reviewQuota(user *models.User) (*models.ReviewQuota, error) {
+	quota := &models.ReviewQuota{NewPerDay: user.NewPerDay, ReviewsPerDay: user.ReviewsPerDay}
+	err := s.db.QueryRow(`
+		SELECT COUNT(CASE WHEN first_review >= datetime('now', 'start of day') THEN 1 END),
+		       COUNT(CASE WHEN first_review < datetime('now', 'start of day') THEN 1 END)
+		FROM (
+			SELECT MIN(datetime(reviewed_at)) AS first_review
+			FROM review_history
+			WHERE user_id = ?
+			GROUP BY word_id, card_type
+			HAVING MAX(datetime(reviewed_at)) >= datetime('now', 'start of day')
+		)
+	`, user.ID).Scan(&quota.NewToday, &quota.ReviewsToday)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count today's reviews: %w", err)
+	}
+
+	quota.NewRemaining = max(quota.NewPerDay-quota.NewToday, 0)
+	quota.ReviewsRemaining = max(quota.ReviewsPerDay-quota.ReviewsToday, 0)
+	return quota, nil
+}
+
+// SubmitReview processes a review and updates the user's progress using their chosen scheduler
+func (s *ReviewService) SubmitReview(username, wordStr, cardType string, quality int) (*models.UserWord, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	userWord, _, err := s.submitReview(tx, username, wordStr, cardType, quality)
+	if err != nil {
+		return nil, err
 	}
 
-	return dueWords, nil
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Return updated user word
+	return s.vocabularyService.GetUserWord(userWord.UserID, userWord.WordID, userWord.CardType)
 }
 
-// SubmitReview processes a review and updates the user's progress using SM-2 algorithm
-func (s *ReviewService) SubmitReview(username, wordStr string, quality int) (*models.UserWord, error) {
+// submitReview records a review in tx, so callers can make other changes with it atomically.
+// It returns the card as it was before the review and the review's history ID.
+func (s *ReviewService) submitReview(tx *sql.Tx, username, wordStr, cardType string, quality int) (*models.UserWord, int64, error) {
 	// Validate quality rating
 	if quality < 0 || quality > 5 {
-		return nil, fmt.Errorf("quality must be between 0 and 5")
+		return nil, 0, fmt.Errorf("quality must be between 0 and 5")
+	}
+	if cardType == "" {
+		cardType = models.CardRecognition
+	}
+	if !slices.Contains(CardTypes, cardType) {
+		return nil, 0, fmt.Errorf("unknown card type")
 	}
 
 	// Get user
 	user, err := s.userService.GetUser(username)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Get word ID
 	word, err := s.vocabularyService.wordService.GetWord(wordStr)
 	if err != nil {
-		return nil, fmt.Errorf("word not found: %w", err)
+		return nil, 0, fmt.Errorf("word not found: %w", err)
 	}
 
 	// Get current user word data
-	userWord, err := s.vocabularyService.GetUserWord(user.ID, word.ID)
+	userWord, err := s.vocabularyService.GetUserWord(user.ID, word.ID, cardType)
 	if err != nil {
-		return nil, fmt.Errorf("word not in user's study list: %w", err)
+		return nil, 0, fmt.Errorf("word not in user's study list: %w", err)
 	}
 
-	// Calculate new values using SM-2 algorithm
-	newEaseFactor, newInterval, newStatus := s.calculateSM2(
-		userWord.EaseFactor,
-		userWord.IntervalDays,
-		quality,
-		userWord.Status,
-	)
-
-	// Calculate next review date
-	nextReview := time.Now().Add(time.Duration(newInterval) * 24 * time.Hour)
+	// Calculate new values with the user's chosen algorithm
+	scheduler, err := s.schedulerFor(user)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to schedule review: %w", err)
+	}
+	steps, err := ParseLearningSteps(user.LearningSteps, user.RelearningSteps)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to schedule review: %w", err)
+	}
+	now := time.Now()
+	next, nextReview := steps.Next(scheduler, cardState(userWord), quality, now)
+	if next.Step == 0 {
+		// Fuzz day-based intervals toward the user's least busy days
+		nextReview, err = s.balancedReviewDate(user.ID, userWord.ID, now, next.IntervalDays)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
 
-	// Start transaction
-	tx, err := s.db.Begin()
+	// Keep the state before the review, so it can be undone
+	prior, err := json.Marshal(priorState(userWord))
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, 0, fmt.Errorf("failed to encode review state: %w", err)
 	}
-	defer tx.Rollback()
 
 	// Update user_words table
 	_, err = tx.Exec(`
 		UPDATE user_words
-		SET ease_factor = ?, interval_days = ?, next_review_date = ?, status = ?
+		SET ease_factor = ?, interval_days = ?, leitner_box = ?, learning_step = ?, relearning = ?,
+		    fsrs_stability = ?, fsrs_difficulty = ?, next_review_date = ?, last_reviewed_at = ?, status = ?
 		WHERE id = ?
-	`, newEaseFactor, newInterval, nextReview, newStatus, userWord.ID)
+	`, next.EaseFactor, next.IntervalDays, next.LeitnerBox, next.Step, next.Relearning,
+		next.Stability, next.Difficulty, nextReview, now, next.Status, userWord.ID)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user word: %w", err)
+		return nil, 0, fmt.Errorf("failed to update user word: %w", err)
 	}
 
 	// Insert into review_history
+	result, err := tx.Exec(`
+		INSERT INTO review_history (user_id, word_id, card_type, reviewed_at, quality, interval_days, ease_factor, prior_state)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, user.ID, word.ID, cardType, now, quality, next.IntervalDays, next.EaseFactor, string(prior))
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to insert review history: %w", err)
+	}
+
+	reviewID, err := result.LastInsertId()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get review ID: %w", err)
+	}
+
+	return userWord, reviewID, nil
+}
+
+// undoSessionAnswer puts a review session's card back in the queue when its answer is undone,
+// dropping the copy queued again after a failed answer and reopening the session if that card
+// ended it
+func undoSessionAnswer(tx *sql.Tx, review *models.ReviewHistory) error {
+	var sessionID, position int64
+	err := tx.QueryRow(`
+		SELECT session_id, position FROM review_session_cards WHERE review_id = ?
+	`, review.ID).Scan(&sessionID, &position)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get session card: %w", err)
+	}
+
 	_, err = tx.Exec(`
-		INSERT INTO review_history (user_id, word_id, reviewed_at, quality, interval_days, ease_factor)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, user.ID, word.ID, time.Now(), quality, newInterval, newEaseFactor)
+		UPDATE review_session_cards SET quality = NULL, answered_at = NULL, review_id = NULL
+		WHERE session_id = ? AND position = ?
+	`, sessionID, position)
+	if err != nil {
+		return fmt.Errorf("failed to restore session card: %w", err)
+	}
+	_, err = tx.Exec(`
+		DELETE FROM review_session_cards
+		WHERE session_id = ? AND word_id = ? AND card_type = ? AND position > ? AND answered_at IS NULL
+	`, sessionID, review.WordID, review.CardType, position)
+	if err != nil {
+		return fmt.Errorf("failed to remove queued session card: %w", err)
+	}
 
+	// A session ended early keeps other unanswered cards and stays ended
+	_, err = tx.Exec(`
+		UPDATE review_sessions SET ended_at = NULL
+		WHERE id = ? AND NOT EXISTS (
+			SELECT 1 FROM review_session_cards WHERE session_id = ? AND answered_at IS NULL AND position != ?
+		)
+	`, sessionID, sessionID, position)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert review history: %w", err)
+		return fmt.Errorf("failed to reopen session: %w", err)
 	}
+	return nil
+}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+// GradeReview grades a typed answer to one of a word's cards and submits the review with the
+// quality it earns
+func (s *ReviewService) GradeReview(username, wordStr, cardType, answer string, responseTime time.Duration) (*models.UserWord, *models.AnswerGrade, error) {
+	grade, err := s.gradeTypedAnswer(username, wordStr, cardType, answer, responseTime)
+	if err != nil {
+		return nil, nil, err
 	}
+	userWord, err := s.SubmitReview(username, wordStr, cardType, grade.Quality)
+	if err != nil {
+		return nil, nil, err
+	}
+	return userWord, grade, nil
+}
 
-	// Return updated user word
-	return s.vocabularyService.GetUserWord(user.ID, word.ID)
-}
-
-// calculateSM2 implements the SM-2 (SuperMemo 2) algorithm
-// Returns: (newEaseFactor, newIntervalDays, newStatus)
-func (s *ReviewService) calculateSM2(currentEF float64, currentInterval int, quality int, currentStatus string) (float64, int, string) {
-	var newEF float64
-	var newInterval int
-	var newStatus string
-
-	// Calculate new ease factor
-	// EF' = EF + (0.1 - (5 - q) * (0.08 + (5 - q) * 0.02))
-	newEF = currentEF + (0.1 - float64(5-quality)*(0.08+float64(5-quality)*0.02))
-
-	// Minimum ease factor is 1.3
-	if newEF < 1.3 {
-		newEF = 1.3
-	}
-
-	// Calculate new interval based on quality
-	if quality < 3 {
-		// Failed recall - reset to 1 day
-		newInterval = 1
-		newStatus = "learning"
-	} else {
-		// Successful recall
-		if currentInterval == 1 {
-			// Second review after successful first
-			newInterval = 6
-		} else {
-			// Subsequent reviews
-			newInterval = int(math.Round(float64(currentInterval) * newEF))
-		}
+// gradeTypedAnswer grades a typed answer to one of a word's cards without reviewing it
+func (s *ReviewService) gradeTypedAnswer(username, wordStr, cardType, answer string, responseTime time.Duration) (*models.AnswerGrade, error) {
+	if cardType == "" {
+		cardType = models.CardRecognition
+	}
+	if !slices.Contains(CardTypes, cardType) {
+		return nil, fmt.Errorf("unknown card type")
+	}
 
-		// Update status based on interval
-		if newInterval < 21 {
-			newStatus = "reviewing"
-		} else {
-			newStatus = "mastered"
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+	word, err := s.vocabularyService.wordService.GetWord(wordStr)
+	if err != nil {
+		return nil, fmt.Errorf("word not found: %w", err)
+	}
+	notes, err := s.vocabularyService.loadNotes(user.ID, word.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return gradeAnswer(cardType, word, notes[word.ID], answer, responseTime), nil
+}
+
+// reviewState is a study list word's scheduling state as stored with each review
+type reviewState struct {
+	Status         string     `json:"status"`
+	NextReviewDate time.Time  `json:"next_review_date"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	EaseFactor     float64    `json:"ease_factor"`
+	IntervalDays   int        `json:"interval_days"`
+	LeitnerBox     int        `json:"leitner_box"`
+	LearningStep   int        `json:"learning_step"`
+	Relearning     bool       `json:"relearning"`
+	Stability      float64    `json:"stability"`
+	Difficulty     float64    `json:"difficulty"`
+}
+
+func priorState(uw *models.UserWord) reviewState {
+	return reviewState{
+		Status:         uw.Status,
+		NextReviewDate: uw.NextReviewDate,
+		LastReviewedAt: uw.LastReviewedAt,
+		EaseFactor:     uw.EaseFactor,
+		IntervalDays:   uw.IntervalDays,
+		LeitnerBox:     uw.LeitnerBox,
+		LearningStep:   uw.LearningStep,
+		Relearning:     uw.Relearning,
+		Stability:      uw.Stability,
+		Difficulty:     uw.Difficulty,
+	}
+}
+
+// UndoReview reverts a review: the word gets back the scheduling state it had before, and the
+// review is removed from the history. With reviewID 0, the user's most recent review is undone.
+// Only a word's latest review can be undone, so earlier ones are undone one at a time.
+func (s *ReviewService) UndoReview(username string, reviewID int64) (*models.ReviewHistory, *models.UserWord, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if reviewID == 0 {
+		err = tx.QueryRow(`
+			SELECT id FROM review_history WHERE user_id = ? ORDER BY id DESC LIMIT 1
+		`, user.ID).Scan(&reviewID)
+		if err == sql.ErrNoRows {
+			return nil, nil, fmt.Errorf("review not found")
 		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get last review: %w", err)
+		}
+	}
+
+	review := &models.ReviewHistory{}
+	var prior sql.NullString
+	err = tx.QueryRow(`
+		SELECT rh.id, rh.user_id, rh.word_id, w.word, rh.card_type, rh.reviewed_at, rh.quality,
+		       rh.interval_days, rh.ease_factor, rh.prior_state
+		FROM review_history rh
+		JOIN words w ON rh.word_id = w.id
+		WHERE rh.id = ? AND rh.user_id = ?
+	`, reviewID, user.ID).Scan(&review.ID, &review.UserID, &review.WordID, &review.Word, &review.CardType,
+		&review.ReviewedAt, &review.Quality, &review.IntervalDays, &review.EaseFactor, &prior)
+	if err == sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("review not found")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get review: %w", err)
+	}
+
+	var latest int64
+	err = tx.QueryRow(`
+		SELECT MAX(id) FROM review_history WHERE user_id = ? AND word_id = ? AND card_type = ?
+	`, user.ID, review.WordID, review.CardType).Scan(&latest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if latest != review.ID {
+		return nil, nil, fmt.Errorf("only the latest review of a card can be undone")
+	}
+	// Reviews recorded before states were kept can't be undone
+	if !prior.Valid {
+		return nil, nil, fmt.Errorf("review cannot be undone")
 	}
 
-	// Ensure minimum interval is 1 day
-	if newInterval < 1 {
-		newInterval = 1
+	var state reviewState
+	if err := json.Unmarshal([]byte(prior.String), &state); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode review state: %w", err)
 	}
 
-	return newEF, newInterval, newStatus
+	result, err := tx.Exec(`
+		UPDATE user_words
+		SET status = ?, next_review_date = ?, last_reviewed_at = ?, ease_factor = ?, interval_days = ?,
+		    leitner_box = ?, learning_step = ?, relearning = ?, fsrs_stability = ?, fsrs_difficulty = ?
+		WHERE user_id = ? AND word_id = ? AND card_type = ?
+	`, state.Status, state.NextReviewDate, state.LastReviewedAt, state.EaseFactor, state.IntervalDays,
+		state.LeitnerBox, state.LearningStep, state.Relearning, state.Stability, state.Difficulty,
+		user.ID, review.WordID, review.CardType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to restore user word: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, nil, fmt.Errorf("word not in user's study list")
+	}
+
+	if _, err := tx.Exec(`DELETE FROM review_history WHERE id = ?`, review.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to remove review: %w", err)
+	}
+
+	if err := undoSessionAnswer(tx, review); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	userWord, err := s.vocabularyService.GetUserWord(user.ID, review.WordID, review.CardType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return review, userWord, nil
 }
 
 // GetReviewHistory retrieves review history for a user's word
@@ -198,7 +477,7 @@ func (s *ReviewService) GetReviewHistory(username, wordStr string) ([]models.Rev
 
 	// Query review history
 	rows, err := s.db.Query(`
-		SELECT rh.id, rh.user_id, rh.word_id, w.word, rh.reviewed_at, rh.quality,
+		SELECT rh.id, rh.user_id, rh.word_id, w.word, rh.card_type, rh.reviewed_at, rh.quality,
 		       rh.interval_days, rh.ease_factor
 		FROM review_history rh
 		JOIN words w ON rh.word_id = w.id
@@ -214,7 +493,7 @@ func (s *ReviewService) GetReviewHistory(username, wordStr string) ([]models.Rev
 	var history []models.ReviewHistory
 	for rows.Next() {
 		var rh models.ReviewHistory
-		err := rows.Scan(&rh.ID, &rh.UserID, &rh.WordID, &rh.Word, &rh.ReviewedAt,
+		err := rows.Scan(&rh.ID, &rh.UserID, &rh.WordID, &rh.Word, &rh.CardType, &rh.ReviewedAt,
 			&rh.Quality, &rh.IntervalDays, &rh.EaseFactor)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan review history: %w", err)
@@ -224,3 +503,199 @@ func (s *ReviewService) GetReviewHistory(username, wordStr string) ([]models.Rev
 
 	return history, nil
 }
+
+// schedulerFor returns the user's scheduling algorithm, with their fitted FSRS weights
+func (s *ReviewService) schedulerFor(user *models.User) (Scheduler, error) {
+	scheduler, err := GetScheduler(user.Scheduler)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := scheduler.(FSRSScheduler); ok {
+		weights, err := s.loadFSRSWeights(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		scheduler = FSRSScheduler{Weights: weights}
+	}
+	return scheduler, nil
+}
+
+// loadFSRSWeights returns the weights fitted to a user's reviews, or nil if there are none
+func (s *ReviewService) loadFSRSWeights(userID int64) ([]float64, error) {
+	var data sql.NullString
+	err := s.db.QueryRow(`SELECT fsrs_weights FROM users WHERE id = ?`, userID).Scan(&data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FSRS weights: %w", err)
+	}
+	if !data.Valid {
+		return nil, nil
+	}
+
+	var weights []float64
+	if err := json.Unmarshal([]byte(data.String), &weights); err != nil {
+		return nil, fmt.Errorf("failed to parse FSRS weights: %w", err)
+	}
+	return weights, nil
+}
+
+// GetFSRSParameters returns the FSRS weights a user's reviews are scheduled with
+func (s *ReviewService) GetFSRSParameters(username string) (*models.FSRSParameters, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	weights, err := s.loadFSRSWeights(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &models.FSRSParameters{Weights: weights, Fitted: weights != nil, DesiredRetention: DesiredRetention}
+	if weights == nil {
+		params.Weights = DefaultFSRSWeights
+	}
+	return params, nil
+}
+
+// historyReview is a stored review with the state it started from, if recorded
+type historyReview struct {
+	fsrsReview
+	prior *reviewState
+}
+
+// scheduledReviews keeps the reviews of one card that went through the scheduler, so FSRS is
+// fitted to the process it schedules: reviews outside the learning steps, and the review that
+// graduates a new card from them. Other step reviews, minutes apart, are left out. Reviews
+// stored without their prior state are kept once per day, as the reference FSRS optimizer does.
+// currentStep is the card's learning step now, or null if it is no longer studied.
+func scheduledReviews(card []historyReview, currentStep sql.NullInt64) []fsrsReview {
+	var reviews []fsrsReview
+	for i, review := range card {
+		switch {
+		case review.prior == nil:
+			if n := len(reviews); n > 0 && sameDay(reviews[n-1].at, review.at) {
+				continue
+			}
+		case review.prior.LearningStep == 0:
+		case review.prior.Relearning:
+			// Leaving relearning resumes the old interval without the scheduler
+			continue
+		default:
+			// A learning step review graduates when the card leaves the steps
+			graduated := currentStep.Valid && currentStep.Int64 == 0
+			if i+1 < len(card) && card[i+1].prior != nil {
+				graduated = card[i+1].prior.LearningStep == 0
+			}
+			if !graduated {
+				continue
+			}
+		}
+		reviews = append(reviews, review.fsrsReview)
+	}
+	return reviews
+}
+
+// sameDay reports whether two times fall on the same UTC day
+func sameDay(a, b time.Time) bool {
+	return a.UTC().Format("2006-01-02") == b.UTC().Format("2006-01-02")
+}
+
+// OptimizeFSRS fits FSRS weights to a user's review history and, if save is set, schedules
+// their future FSRS reviews with them
+func (s *ReviewService) OptimizeFSRS(username string, save bool) (*models.FSRSOptimization, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT rh.word_id, rh.card_type, rh.reviewed_at, rh.quality, rh.prior_state, uw.learning_step
+		FROM review_history rh
+		LEFT JOIN user_words uw
+			ON uw.user_id = rh.user_id AND uw.word_id = rh.word_id AND uw.card_type = rh.card_type
+		WHERE rh.user_id = ?
+		ORDER BY rh.word_id, rh.card_type, rh.reviewed_at, rh.id
+	`, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review history: %w", err)
+	}
+	defer rows.Close()
+
+	// Each card has its own history
+	var histories [][]fsrsReview
+	var card []historyReview
+	var cardStep sql.NullInt64
+	lastWordID, lastCardType := int64(-1), ""
+	for rows.Next() {
+		var wordID int64
+		var cardType string
+		var review historyReview
+		var quality int
+		var prior sql.NullString
+		var step sql.NullInt64
+		if err := rows.Scan(&wordID, &cardType, &review.at, &quality, &prior, &step); err != nil {
+			return nil, fmt.Errorf("failed to scan review history: %w", err)
+		}
+		review.grade = fsrsGrade(quality)
+		if prior.Valid {
+			review.prior = &reviewState{}
+			if err := json.Unmarshal([]byte(prior.String), review.prior); err != nil {
+				return nil, fmt.Errorf("failed to decode review state: %w", err)
+			}
+		}
+		if wordID != lastWordID || cardType != lastCardType {
+			if reviews := scheduledReviews(card, cardStep); len(reviews) > 0 {
+				histories = append(histories, reviews)
+			}
+			card = nil
+			lastWordID, lastCardType = wordID, cardType
+		}
+		card = append(card, review)
+		cardStep = step
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read review history: %w", err)
+	}
+	if reviews := scheduledReviews(card, cardStep); len(reviews) > 0 {
+		histories = append(histories, reviews)
+	}
+
+	current, err := s.loadFSRSWeights(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		current = DefaultFSRSWeights
+	}
+	before := evaluateFSRS(current, histories)
+	if before.reviews < minFSRSReviews {
+		return nil, fmt.Errorf("not enough review history")
+	}
+
+	weights := fitFSRS(histories, before.reviews)
+	after := evaluateFSRS(weights, histories)
+
+	result := &models.FSRSOptimization{
+		Weights:           weights,
+		Reviews:           after.reviews,
+		Words:             len(histories),
+		LogLossBefore:     before.logLoss,
+		LogLoss:           after.logLoss,
+		ActualRetention:   float64(after.recalled) / float64(after.reviews),
+		ExpectedRetention: after.predicted,
+		DesiredRetention:  DesiredRetention,
+	}
+
+	if save {
+		data, err := json.Marshal(weights)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.db.Exec(`UPDATE users SET fsrs_weights = ? WHERE id = ?`, string(data), user.ID); err != nil {
+			return nil, fmt.Errorf("failed to save FSRS weights: %w", err)
+		}
+		result.Saved = true
+	}
+
+	return result, nil
+}
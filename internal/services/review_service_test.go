@@ -0,0 +1,89 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestScheduledReviews(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	step := func(n int, relearning bool) *reviewState {
+		return &reviewState{LearningStep: n, Relearning: relearning}
+	}
+	review := func(d time.Duration, grade int, prior *reviewState) historyReview {
+		return historyReview{fsrsReview: fsrsReview{at: at(d), grade: grade}, prior: prior}
+	}
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name        string
+		card        []historyReview
+		currentStep sql.NullInt64
+		want        []time.Time
+	}{
+		{
+			name: "learning steps before graduation are skipped",
+			card: []historyReview{
+				review(0, gradeHard, step(1, false)),
+				review(time.Minute, gradeGood, step(1, false)),
+				review(11*time.Minute, gradeGood, step(2, false)),
+				review(day, gradeGood, step(0, false)),
+			},
+			currentStep: sql.NullInt64{Int64: 0, Valid: true},
+			want:        []time.Time{at(11 * time.Minute), at(day)},
+		},
+		{
+			name: "the last review graduates when the card has left the steps",
+			card: []historyReview{
+				review(0, gradeGood, step(1, false)),
+				review(time.Minute, gradeGood, step(2, false)),
+			},
+			currentStep: sql.NullInt64{Int64: 0, Valid: true},
+			want:        []time.Time{at(time.Minute)},
+		},
+		{
+			name: "a card still in its steps has no scheduled reviews",
+			card: []historyReview{
+				review(0, gradeGood, step(1, false)),
+			},
+			currentStep: sql.NullInt64{Int64: 2, Valid: true},
+			want:        nil,
+		},
+		{
+			name: "relearning steps are skipped",
+			card: []historyReview{
+				review(0, gradeGood, step(0, false)),
+				review(3*day, gradeAgain, step(0, false)),
+				review(3*day+10*time.Minute, gradeGood, step(1, true)),
+				review(4*day, gradeGood, step(0, false)),
+			},
+			currentStep: sql.NullInt64{Int64: 0, Valid: true},
+			want:        []time.Time{at(0), at(3 * day), at(4 * day)},
+		},
+		{
+			name: "reviews without a prior state are kept once per day",
+			card: []historyReview{
+				review(0, gradeGood, nil),
+				review(time.Minute, gradeGood, nil),
+				review(day, gradeGood, nil),
+			},
+			want: []time.Time{at(0), at(day)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scheduledReviews(tt.card, tt.currentStep)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d reviews, want %d", len(got), len(tt.want))
+			}
+			for i, review := range got {
+				if !review.at.Equal(tt.want[i]) {
+					t.Errorf("review %d at %v, want %v", i, review.at, tt.want[i])
+				}
+			}
+		})
+	}
+}
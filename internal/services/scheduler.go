@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// Word statuses in a study list
+const (
+	StatusLearning  = "learning"
+	StatusReviewing = "reviewing"
+	StatusMastered  = "mastered"
+)
+
+// masteredInterval is the interval, in days, from which a word counts as mastered
+const masteredInterval = 21
+
+// CardState is the scheduling state of a word in a user's study list. Each scheduler reads and
+// updates the fields it uses and carries the others over, so users can switch algorithms
+// without losing progress.
+type CardState struct {
+	Status       string
+	EaseFactor   float64
+	IntervalDays int
+	// LeitnerBox is the word's box for the Leitner scheduler, from 1
+	LeitnerBox int
+	// Step is the word's learning step, from 1, while it goes through its learning steps
+	// (relearning steps if Relearning) and 0 once it is on day-based intervals. Schedulers only
+	// see words on intervals, and words graduating from their learning steps.
+	Step       int
+	Relearning bool
+	// Stability (days until recall drops to 90%) and Difficulty (1-10) are FSRS's memory model;
+	// zero until the word's first FSRS review
+	Stability  float64
+	Difficulty float64
+	// LastReview is when the word was last reviewed
+	LastReview time.Time
+}
+
+// cardState returns a study list word's scheduling state. Words last reviewed before reviews
+// were timestamped are assumed to have been reviewed on schedule.
+func cardState(uw *models.UserWord) CardState {
+	state := CardState{
+		Status:       uw.Status,
+		EaseFactor:   uw.EaseFactor,
+		IntervalDays: uw.IntervalDays,
+		LeitnerBox:   uw.LeitnerBox,
+		Step:         uw.LearningStep,
+		Relearning:   uw.Relearning,
+		Stability:    uw.Stability,
+		Difficulty:   uw.Difficulty,
+	}
+	if uw.LastReviewedAt != nil {
+		state.LastReview = *uw.LastReviewedAt
+	} else {
+		state.LastReview = uw.NextReviewDate.Add(-time.Duration(uw.IntervalDays) * 24 * time.Hour)
+	}
+	return state
+}
+
+// Scheduler decides when a word is reviewed next
+type Scheduler interface {
+	// Name identifies the algorithm in user settings
+	Name() string
+	// Next returns the state after a review graded quality (0-5) at now
+	Next(state CardState, quality int, now time.Time) CardState
+}
+
+// DefaultScheduler is the algorithm for users who haven't chosen one
+const DefaultScheduler = "sm2"
+
+// schedulers lists the available algorithms by name
+var schedulers = map[string]Scheduler{
+	"sm2":     SM2Scheduler{},
+	"leitner": LeitnerScheduler{},
+	"fixed":   FixedIntervalScheduler{},
+	"fsrs":    FSRSScheduler{},
+}
+
+// GetScheduler returns the named algorithm
+func GetScheduler(name string) (Scheduler, error) {
+	scheduler, ok := schedulers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scheduler")
+	}
+	return scheduler, nil
+}
+
+// SchedulerNames returns the algorithm names in alphabetical order
+func SchedulerNames() []string {
+	names := make([]string, 0, len(schedulers))
+	for name := range schedulers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// daysSince is the time from then to now in days
+func daysSince(then, now time.Time) float64 {
+	return max(now.Sub(then).Hours()/24, 0)
+}
+
+// nextReviewDate is when a word with the given interval is due again
+func nextReviewDate(now time.Time, intervalDays int) time.Time {
+	return now.Add(time.Duration(intervalDays) * 24 * time.Hour)
+}
+
+// statusForInterval is the status of a word recalled successfully
+func statusForInterval(intervalDays int) string {
+	if intervalDays < masteredInterval {
+		return StatusReviewing
+	}
+	return StatusMastered
+}
+
+// SM2Scheduler implements the SM-2 (SuperMemo 2) algorithm
+type SM2Scheduler struct{}
+
+func (SM2Scheduler) Name() string { return "sm2" }
+
+func (SM2Scheduler) Next(state CardState, quality int, now time.Time) CardState {
+	// Calculate new ease factor
+	// EF' = EF + (0.1 - (5 - q) * (0.08 + (5 - q) * 0.02))
+	state.EaseFactor += 0.1 - float64(5-quality)*(0.08+float64(5-quality)*0.02)
+
+	// Minimum ease factor is 1.3
+	if state.EaseFactor < 1.3 {
+		state.EaseFactor = 1.3
+	}
+
+	// Calculate new interval based on quality
+	if quality < 3 {
+		// Failed recall - reset to 1 day
+		state.IntervalDays = 1
+		state.Status = StatusLearning
+		return state
+	}
+
+	if state.IntervalDays == 1 {
+		// Second review after successful first
+		state.IntervalDays = 6
+	} else {
+		// Subsequent reviews
+		state.IntervalDays = int(math.Round(float64(state.IntervalDays) * state.EaseFactor))
+	}
+
+	// Ensure minimum interval is 1 day
+	if state.IntervalDays < 1 {
+		state.IntervalDays = 1
+	}
+	state.Status = statusForInterval(state.IntervalDays)
+	return state
+}
+
+// leitnerIntervals are the days between reviews for each box
+var leitnerIntervals = []int{1, 3, 7, 14, 30}
+
+// LeitnerScheduler moves a word up one box when it is recalled and back to the first box when
+// it isn't; each box has a fixed review interval
+type LeitnerScheduler struct{}
+
+func (LeitnerScheduler) Name() string { return "leitner" }
+
+func (LeitnerScheduler) Next(state CardState, quality int, now time.Time) CardState {
+	if quality < 3 {
+		state.LeitnerBox = 1
+	} else {
+		state.LeitnerBox = min(max(state.LeitnerBox, 1)+1, len(leitnerIntervals))
+	}
+	state.IntervalDays = leitnerIntervals[state.LeitnerBox-1]
+
+	switch {
+	case state.LeitnerBox == 1:
+		state.Status = StatusLearning
+	case state.LeitnerBox == len(leitnerIntervals):
+		state.Status = StatusMastered
+	default:
+		state.Status = StatusReviewing
+	}
+	return state
+}
+
+// fixedIntervals is the ladder of intervals, in days, the fixed-interval scheduler climbs
+var fixedIntervals = []int{1, 3, 7, 14, 30, 60, 120}
+
+// FixedIntervalScheduler moves a recalled word to the next interval on a fixed ladder and a
+// forgotten one back to a day, whatever the grade
+type FixedIntervalScheduler struct{}
+
+func (FixedIntervalScheduler) Name() string { return "fixed" }
+
+func (FixedIntervalScheduler) Next(state CardState, quality int, now time.Time) CardState {
+	if quality < 3 {
+		state.IntervalDays = fixedIntervals[0]
+		state.Status = StatusLearning
+		return state
+	}
+
+	next := fixedIntervals[len(fixedIntervals)-1]
+	for _, days := range fixedIntervals {
+		if days > state.IntervalDays {
+			next = days
+			break
+		}
+	}
+	state.IntervalDays = next
+	state.Status = statusForInterval(next)
+	return state
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/words-api/words/internal/models"
+)
+
+// SessionService runs review sessions: a queue of due words fixed when the session starts,
+// answered one card at a time. Failed words go to the back of the queue.
+type SessionService struct {
+	db                *sql.DB
+	userService       *UserService
+	reviewService     *ReviewService
+	vocabularyService *VocabularyService
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(db *sql.DB) *SessionService {
+	return &SessionService{
+		db:                db,
+		userService:       NewUserService(db),
+		reviewService:     NewReviewService(db),
+		vocabularyService: NewVocabularyService(db),
+	}
+}
+
+// sessionCard is a queued card as stored
+type sessionCard struct {
+	position int
+	wordID   int64
+	cardType string
+	word     string
+}
+
+// Cards only count while their word is still in the user's study list; words removed during
+// a session are skipped
+const sessionCardJoins = `
+	FROM review_session_cards c
+	JOIN review_sessions rs ON c.session_id = rs.id
+	JOIN user_words uw ON uw.user_id = rs.user_id AND uw.word_id = c.word_id AND uw.card_type = c.card_type
+`
+
+// CreateSession starts a session with the user's due words, in review queue order and within
+// their daily limits, up to limit words unless it is 0
+func (s *SessionService) CreateSession(username string, limit int) (*models.ReviewSession, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	due, _, err := s.reviewService.GetDueWords(username)
+	if err != nil {
+		return nil, err
+	}
+	if len(due) == 0 {
+		return nil, fmt.Errorf("no words due for review")
+	}
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO review_sessions (user_id, started_at) VALUES (?, ?)
+	`, user.ID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	sessionID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session ID: %w", err)
+	}
+
+	for i, uw := range due {
+		_, err := tx.Exec(`
+			INSERT INTO review_session_cards (session_id, position, word_id, card_type) VALUES (?, ?, ?, ?)
+		`, sessionID, i+1, uw.WordID, uw.CardType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to queue word: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.getSession(user.ID, sessionID)
+}
+
+// GetSessions retrieves a user's most recent sessions, newest first
+func (s *SessionService) GetSessions(username string, limit int) ([]models.ReviewSession, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id FROM review_sessions WHERE user_id = ? ORDER BY started_at DESC, id DESC LIMIT ?
+	`, user.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	sessions := make([]models.ReviewSession, 0, len(ids))
+	for _, id := range ids {
+		session, err := s.getSession(user.ID, id)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, nil
+}
+
+// GetSession retrieves one of a user's sessions with its summary stats
+func (s *SessionService) GetSession(username string, sessionID int64) (*models.ReviewSession, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+	return s.getSession(user.ID, sessionID)
+}
+
+func (s *SessionService) getSession(userID, sessionID int64) (*models.ReviewSession, error) {
+	session := &models.ReviewSession{}
+	var endedAt sql.NullTime
+	err := s.db.QueryRow(`
+		SELECT id, user_id, started_at, ended_at FROM review_sessions WHERE id = ? AND user_id = ?
+	`, sessionID, userID).Scan(&session.ID, &session.UserID, &session.StartedAt, &endedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	var average sql.NullFloat64
+	err = s.db.QueryRow(`
+		SELECT COUNT(DISTINCT c.word_id), COUNT(*), COUNT(c.answered_at),
+		       COUNT(CASE WHEN c.quality >= 3 THEN 1 END), COUNT(CASE WHEN c.quality < 3 THEN 1 END),
+		       AVG(c.quality)
+	`+sessionCardJoins+`
+		WHERE c.session_id = ?
+	`, sessionID).Scan(&session.Words, &session.Cards, &session.Answered, &session.Correct, &session.Failed, &average)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize session: %w", err)
+	}
+	session.AverageQuality = average.Float64
+	session.Remaining = session.Cards - session.Answered
+
+	end := time.Now()
+	if endedAt.Valid {
+		session.EndedAt = &endedAt.Time
+		end = endedAt.Time
+	}
+	session.DurationSeconds = int64(end.Sub(session.StartedAt).Seconds())
+	return session, nil
+}
+
+// currentCard returns the first unanswered card, or nil when there are none left
+func (s *SessionService) currentCard(sessionID int64) (*sessionCard, error) {
+	card := &sessionCard{}
+	err := s.db.QueryRow(`
+		SELECT c.position, c.word_id, c.card_type, w.word
+	`+sessionCardJoins+`
+		JOIN words w ON c.word_id = w.id
+		WHERE c.session_id = ? AND c.answered_at IS NULL
+		ORDER BY c.position
+		LIMIT 1
+	`, sessionID).Scan(&card.position, &card.wordID, &card.cardType, &card.word)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next card: %w", err)
+	}
+	return card, nil
+}
+
+// NextCard returns the session's current card with its prompt and answer, full dictionary
+// entry and the user's notes, or nil once the session is over
+func (s *SessionService) NextCard(username string, sessionID int64) (*models.SessionCard, *models.ReviewSession, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, nil, err
+	}
+	session, err := s.getSession(user.ID, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session.EndedAt != nil {
+		return nil, session, nil
+	}
+
+	card, err := s.currentCard(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if card == nil {
+		session, err = s.endSession(user.ID, sessionID)
+		return nil, session, err
+	}
+
+	userWord, err := s.vocabularyService.GetUserWord(user.ID, card.wordID, card.cardType)
+	if err != nil {
+		return nil, nil, err
+	}
+	words := []models.UserWord{*userWord}
+	if err := s.vocabularyService.attachNotes(user.ID, words); err != nil {
+		return nil, nil, err
+	}
+	entry, err := s.vocabularyService.wordService.GetWord(card.word)
+	if err != nil {
+		return nil, nil, fmt.Errorf("word not found: %w", err)
+	}
+
+	var earlier int
+	err = s.db.QueryRow(`
+		SELECT COUNT(*) FROM review_session_cards
+		WHERE session_id = ? AND word_id = ? AND card_type = ? AND position < ?
+	`, sessionID, card.wordID, card.cardType, card.position).Scan(&earlier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get next card: %w", err)
+	}
+
+	prompt, answer := cardFace(card.cardType, entry, words[0].Notes)
+	return &models.SessionCard{
+		SessionID: sessionID,
+		Position:  card.position,
+		Requeued:  earlier > 0,
+		CardType:  card.cardType,
+		Prompt:    prompt,
+		Answer:    answer,
+		UserWord:  &words[0],
+		Entry:     entry,
+	}, session, nil
+}
+
+// Answer reviews the session's current card with a quality rating or a graded typed answer. A
+// failed word is queued again at the back, and the session ends with its last card.
+func (s *SessionService) Answer(username string, sessionID int64, request models.SessionAnswerRequest) (*models.SessionAnswer, error) {
+	if (request.Quality == nil) == (request.Answer == nil) {
+		return nil, fmt.Errorf("give either a quality rating or a typed answer")
+	}
+
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.getSession(user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.EndedAt != nil {
+		return nil, fmt.Errorf("session has ended")
+	}
+
+	card, err := s.currentCard(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if card == nil {
+		if _, err := s.endSession(user.ID, sessionID); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("session has ended")
+	}
+	if request.Word != "" && !strings.EqualFold(strings.TrimSpace(request.Word), card.word) {
+		return nil, fmt.Errorf("answer does not match the current card")
+	}
+
+	var grade *models.AnswerGrade
+	var quality int
+	if request.Answer != nil {
+		responseTime := time.Duration(request.ResponseMS) * time.Millisecond
+		grade, err = s.reviewService.gradeTypedAnswer(username, card.word, card.cardType, *request.Answer, responseTime)
+		if err != nil {
+			return nil, err
+		}
+		quality = grade.Quality
+	} else {
+		quality = *request.Quality
+	}
+
+	// Review the card and mark it answered together, so a retried or concurrent answer
+	// can't review it twice
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	userWord, reviewID, err := s.reviewService.submitReview(tx, username, card.word, card.cardType, quality)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE review_session_cards SET quality = ?, answered_at = ?, review_id = ?
+		WHERE session_id = ? AND position = ? AND answered_at IS NULL
+	`, quality, time.Now(), reviewID, sessionID, card.position)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record answer: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to record answer: %w", err)
+	} else if rows == 0 {
+		return nil, fmt.Errorf("card already answered")
+	}
+
+	requeued := quality < 3
+	if requeued {
+		_, err = tx.Exec(`
+			INSERT INTO review_session_cards (session_id, position, word_id, card_type)
+			SELECT ?, MAX(position) + 1, ?, ? FROM review_session_cards WHERE session_id = ?
+		`, sessionID, card.wordID, card.cardType, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to queue word again: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	userWord, err = s.reviewService.vocabularyService.GetUserWord(userWord.UserID, userWord.WordID, userWord.CardType)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := s.currentCard(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		session, err = s.endSession(user.ID, sessionID)
+	} else {
+		session, err = s.getSession(user.ID, sessionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SessionAnswer{UserWord: userWord, Grade: grade, Requeued: requeued, Session: *session}, nil
+}
+
+// EndSession ends a session early; its unanswered cards stay unanswered
+func (s *SessionService) EndSession(username string, sessionID int64) (*models.ReviewSession, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+	return s.endSession(user.ID, sessionID)
+}
+
+func (s *SessionService) endSession(userID, sessionID int64) (*models.ReviewSession, error) {
+	_, err := s.db.Exec(`
+		UPDATE review_sessions SET ended_at = ? WHERE id = ? AND user_id = ? AND ended_at IS NULL
+	`, time.Now(), sessionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to end session: %w", err)
+	}
+	return s.getSession(userID, sessionID)
+}
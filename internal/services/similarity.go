@@ -0,0 +1,112 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// levenshtein returns the edit distance between two strings, counted in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// similarity returns 1 - normalized edit distance, in the range [0, 1]
+func similarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// soundex returns the American Soundex code for a word (e.g. "effect" -> "E123")
+func soundex(word string) string {
+	codes := map[rune]byte{
+		'b': '1', 'f': '1', 'p': '1', 'v': '1',
+		'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+		'd': '3', 't': '3',
+		'l': '4',
+		'm': '5', 'n': '5',
+		'r': '6',
+	}
+
+	var letters []rune
+	for _, r := range strings.ToLower(word) {
+		if r >= 'a' && r <= 'z' {
+			letters = append(letters, r)
+		}
+	}
+	if len(letters) == 0 {
+		return ""
+	}
+
+	result := []byte{byte(unicode.ToUpper(letters[0]))}
+	last := codes[letters[0]]
+	for _, r := range letters[1:] {
+		code, ok := codes[r]
+		switch {
+		case ok && code != last:
+			result = append(result, code)
+			last = code
+		case !ok && r != 'h' && r != 'w':
+			// Vowels separate duplicate codes; h and w do not
+			last = 0
+		}
+		if len(result) == 4 {
+			break
+		}
+	}
+
+	for len(result) < 4 {
+		result = append(result, '0')
+	}
+	return string(result[:4])
+}
+
+// normalizeIPA strips delimiters and stress marks from an IPA transcription
+func normalizeIPA(ipa string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '[', ']', 'ˈ', 'ˌ', '.', '(', ')', ' ', 'ː':
+			return -1
+		}
+		return r
+	}, ipa)
+}
+
+// phoneticSimilarity scores how alike two words sound, in the range [0, 1].
+// IPA transcriptions are compared when both entries have one; otherwise the
+// Soundex codes of the headwords are compared.
+func phoneticSimilarity(a, b string, ipaA, ipaB string) float64 {
+	ipaA, ipaB = normalizeIPA(ipaA), normalizeIPA(ipaB)
+	if ipaA != "" && ipaB != "" {
+		return similarity(ipaA, ipaB)
+	}
+	return similarity(soundex(a), soundex(b))
+}
@@ -54,9 +54,7 @@ func (s *UserService) CreateUser(username string) (*models.User, error) {
 // GetUser retrieves a user by username
 func (s *UserService) GetUser(username string) (*models.User, error) {
 	user := &models.User{}
-	err := s.db.QueryRow(`
-		SELECT id, username, created_at FROM users WHERE username = ?
-	`, username).Scan(&user.ID, &user.Username, &user.CreatedAt)
+	err := scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE username = ?`, username), user)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
@@ -71,9 +69,7 @@ func (s *UserService) GetUser(username string) (*models.User, error) {
 // GetUserByID retrieves a user by ID
 func (s *UserService) GetUserByID(userID int64) (*models.User, error) {
 	user := &models.User{}
-	err := s.db.QueryRow(`
-		SELECT id, username, created_at FROM users WHERE id = ?
-	`, userID).Scan(&user.ID, &user.Username, &user.CreatedAt)
+	err := scanUser(s.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = ?`, userID), user)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
@@ -85,6 +81,105 @@ func (s *UserService) GetUserByID(userID int64) (*models.User, error) {
 	return user, nil
 }
 
+// userColumns are the columns scanUser reads from users
+const userColumns = `id, username, scheduler, learning_steps, relearning_steps,
+	new_per_day, reviews_per_day, created_at`
+
+// scanUser reads a row selected with userColumns
+func scanUser(row rowScanner, user *models.User) error {
+	var learningSteps, relearningSteps string
+	err := row.Scan(&user.ID, &user.Username, &user.Scheduler, &learningSteps, &relearningSteps,
+		&user.NewPerDay, &user.ReviewsPerDay, &user.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	user.LearningSteps, user.RelearningSteps = strings.Fields(learningSteps), strings.Fields(relearningSteps)
+	return nil
+}
+
+// SetScheduler chooses the algorithm that schedules a user's reviews. Words keep their due
+// dates; the new algorithm takes over from their next review.
+func (s *UserService) SetScheduler(username, name string) (*models.User, error) {
+	if _, err := GetScheduler(name); err != nil {
+		return nil, err
+	}
+
+	user, err := s.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.Exec(`UPDATE users SET scheduler = ? WHERE id = ?`, name, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set scheduler: %w", err)
+	}
+
+	user.Scheduler = name
+	return user, nil
+}
+
+// SetLearningSteps changes a user's learning and relearning steps; a nil list is left as it
+// is. Words already in their steps continue from the step they are at.
+func (s *UserService) SetLearningSteps(username string, learning, relearning []string) (*models.User, error) {
+	user, err := s.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if learning == nil {
+		learning = user.LearningSteps
+	}
+	if relearning == nil {
+		relearning = user.RelearningSteps
+	}
+	steps, err := ParseLearningSteps(learning, relearning)
+	if err != nil {
+		return nil, err
+	}
+	user.LearningSteps, user.RelearningSteps = formatSteps(steps.Learning), formatSteps(steps.Relearning)
+
+	_, err = s.db.Exec(`
+		UPDATE users SET learning_steps = ?, relearning_steps = ? WHERE id = ?
+	`, strings.Join(user.LearningSteps, " "), strings.Join(user.RelearningSteps, " "), user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set learning steps: %w", err)
+	}
+
+	return user, nil
+}
+
+// maxDailyLimit caps the daily new word and review limits
+const maxDailyLimit = 9999
+
+// SetDailyLimits changes how many new words and reviews a user's review queue holds each day;
+// a nil limit is left as it is
+func (s *UserService) SetDailyLimits(username string, newPerDay, reviewsPerDay *int) (*models.User, error) {
+	user, err := s.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if newPerDay != nil {
+		user.NewPerDay = *newPerDay
+	}
+	if reviewsPerDay != nil {
+		user.ReviewsPerDay = *reviewsPerDay
+	}
+	if user.NewPerDay < 0 || user.NewPerDay > maxDailyLimit || user.ReviewsPerDay < 0 || user.ReviewsPerDay > maxDailyLimit {
+		return nil, fmt.Errorf("daily limits must be between 0 and 9999")
+	}
+
+	_, err = s.db.Exec(`
+		UPDATE users SET new_per_day = ?, reviews_per_day = ? WHERE id = ?
+	`, user.NewPerDay, user.ReviewsPerDay, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set daily limits: %w", err)
+	}
+
+	return user, nil
+}
+
 // GetUserStats retrieves learning statistics for a user
 func (s *UserService) GetUserStats(username string) (*models.UserStats, error) {
 	user, err := s.GetUser(username)
@@ -96,9 +191,9 @@ func (s *UserService) GetUserStats(username string) (*models.UserStats, error) {
 		Username: username,
 	}
 
-	// Get total words
+	// Get total words; the other counts are of cards
 	err = s.db.QueryRow(`
-		SELECT COUNT(*) FROM user_words WHERE user_id = ?
+		SELECT COUNT(DISTINCT word_id) FROM user_words WHERE user_id = ?
 	`, user.ID).Scan(&stats.TotalWords)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total words: %w", err)
@@ -107,7 +202,7 @@ func (s *UserService) GetUserStats(username string) (*models.UserStats, error) {
 	// Get words due today
 	err = s.db.QueryRow(`
 		SELECT COUNT(*) FROM user_words
-		WHERE user_id = ? AND next_review_date <= datetime('now')
+		WHERE user_id = ? AND datetime(next_review_date) <= datetime('now')
 	`, user.ID).Scan(&stats.DueToday)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get due words: %w", err)
@@ -3,6 +3,7 @@ package services
 import (
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -25,8 +26,9 @@ func NewVocabularyService(db *sql.DB) *VocabularyService {
 	}
 }
 
-// AddWord adds a word to a user's study list
-func (s *VocabularyService) AddWord(username, wordStr string) (*models.UserWord, error) {
+// AddWord adds a word to a user's study list with the given card types (a recognition card if
+// none are given), keeping any cards it already has, and returns all of the word's cards
+func (s *VocabularyService) AddWord(username, wordStr string, cardTypes []string) ([]models.UserWord, error) {
 	// Get user
 	user, err := s.userService.GetUser(username)
 	if err != nil {
@@ -39,41 +41,168 @@ func (s *VocabularyService) AddWord(username, wordStr string) (*models.UserWord,
 		return nil, fmt.Errorf("word cannot be empty")
 	}
 
+	cardTypes, err = normalizeCardTypes(cardTypes)
+	if err != nil {
+		return nil, err
+	}
+
 	// Ensure word exists in the words table (fetch if needed)
 	word, err := s.wordService.GetWord(wordStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch word: %w", err)
 	}
 
-	// Check if word is already in user's list
-	var existingID int64
-	err = s.db.QueryRow(`
-		SELECT id FROM user_words WHERE user_id = ? AND word_id = ?
-	`, user.ID, word.ID).Scan(&existingID)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.addCards(tx, user, word, cardTypes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return s.getWordCards(user.ID, word.ID)
+}
+
+// SetCardTypes chooses the card types a word in the user's study list is studied with. New
+// cards start from the first learning step; dropped cards lose their schedule but keep their
+// review history.
+func (s *VocabularyService) SetCardTypes(username, wordStr string, cardTypes []string) ([]models.UserWord, error) {
+	if len(cardTypes) == 0 {
+		return nil, fmt.Errorf("at least one card type is required")
+	}
+	cardTypes, err := normalizeCardTypes(cardTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	user, word, err := s.getStudiedWord(username, wordStr)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.addCards(tx, user, word, cardTypes); err != nil {
+		return nil, err
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(cardTypes)), ", ")
+	args := []interface{}{user.ID, word.ID}
+	for _, cardType := range cardTypes {
+		args = append(args, cardType)
+	}
+	_, err = tx.Exec(`
+		DELETE FROM user_words WHERE user_id = ? AND word_id = ? AND card_type NOT IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove cards: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.getWordCards(user.ID, word.ID)
+}
+
+// GetWordCards retrieves the cards a word in the user's study list is studied with, and the
+// card types its entry has the content for
+func (s *VocabularyService) GetWordCards(username, wordStr string) ([]models.UserWord, []string, error) {
+	user, word, err := s.getStudiedWord(username, wordStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cards, err := s.getWordCards(user.ID, word.ID)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if err == nil {
-		// Word already exists, return existing record
-		return s.GetUserWord(user.ID, word.ID)
+	notes, err := s.loadNotes(user.ID, word.ID)
+	if err != nil {
+		return nil, nil, err
 	}
+	return cards, availableCardTypes(word, notes[word.ID]), nil
+}
 
-	// Add word to user's study list
-	nextReview := time.Now().Add(1 * time.Hour) // First review in 1 hour
-	result, err := s.db.Exec(`
-		INSERT INTO user_words (user_id, word_id, added_at, status, next_review_date, ease_factor, interval_days)
-		VALUES (?, ?, ?, 'learning', ?, 2.5, 1)
-	`, user.ID, word.ID, time.Now(), nextReview)
+// addCards adds the card types a word doesn't have yet, due after the first learning step.
+// Each must be one the word's entry has the content for.
+func (s *VocabularyService) addCards(tx *sql.Tx, user *models.User, word *models.Word, cardTypes []string) error {
+	notes, err := s.loadNotes(user.ID, word.ID)
+	if err != nil {
+		return err
+	}
+	available := availableCardTypes(word, notes[word.ID])
+	for _, cardType := range cardTypes {
+		if !slices.Contains(available, cardType) {
+			return fmt.Errorf("card type not available for this word")
+		}
+	}
 
+	steps, err := ParseLearningSteps(user.LearningSteps, user.RelearningSteps)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add word: %w", err)
+		return fmt.Errorf("failed to add word: %w", err)
+	}
+	now := time.Now()
+	nextReview, step := steps.FirstReview(now)
+
+	for _, cardType := range cardTypes {
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO user_words
+				(user_id, word_id, card_type, added_at, status, next_review_date, ease_factor, interval_days, learning_step)
+			VALUES (?, ?, ?, ?, 'learning', ?, 2.5, 1, ?)
+		`, user.ID, word.ID, cardType, now, nextReview, step)
+		if err != nil {
+			return fmt.Errorf("failed to add word: %w", err)
+		}
 	}
+	return nil
+}
 
-	userWordID, err := result.LastInsertId()
+// getWordCards retrieves a word's cards in the order card types are listed
+func (s *VocabularyService) getWordCards(userID, wordID int64) ([]models.UserWord, error) {
+	rows, err := s.db.Query(`
+		SELECT `+userWordColumns+`
+		FROM user_words uw
+		JOIN words w ON uw.word_id = w.id
+		WHERE uw.user_id = ? AND uw.word_id = ?
+	`, userID, wordID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user word ID: %w", err)
+		return nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+	defer rows.Close()
+
+	byType := make(map[string]models.UserWord)
+	for rows.Next() {
+		var uw models.UserWord
+		if err := scanUserWord(rows, &uw); err != nil {
+			return nil, fmt.Errorf("failed to scan card: %w", err)
+		}
+		byType[uw.CardType] = uw
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get cards: %w", err)
 	}
 
-	// Retrieve and return the created record
-	return s.GetUserWordByID(userWordID)
+	var cards []models.UserWord
+	for _, cardType := range CardTypes {
+		if uw, ok := byType[cardType]; ok {
+			cards = append(cards, uw)
+		}
+	}
+	if err := s.attachNotes(userID, cards); err != nil {
+		return nil, err
+	}
+	return cards, nil
 }
 
 // GetUserWords retrieves all words for a user, optionally filtered by status
@@ -85,8 +214,7 @@ func (s *VocabularyService) GetUserWords(username, status string) ([]models.User
 	}
 
 	query := `
-		SELECT uw.id, uw.user_id, uw.word_id, w.word, uw.added_at, uw.status,
-		       uw.next_review_date, uw.ease_factor, uw.interval_days
+		SELECT ` + userWordColumns + `
 		FROM user_words uw
 		JOIN words w ON uw.word_id = w.id
 		WHERE uw.user_id = ?
@@ -99,7 +227,7 @@ func (s *VocabularyService) GetUserWords(username, status string) ([]models.User
 		args = append(args, status)
 	}
 
-	query += " ORDER BY uw.added_at DESC"
+	query += " ORDER BY uw.added_at DESC, uw.word_id, uw.id"
 
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
@@ -110,28 +238,53 @@ func (s *VocabularyService) GetUserWords(username, status string) ([]models.User
 	var userWords []models.UserWord
 	for rows.Next() {
 		var uw models.UserWord
-		err := rows.Scan(&uw.ID, &uw.UserID, &uw.WordID, &uw.Word, &uw.AddedAt,
-			&uw.Status, &uw.NextReviewDate, &uw.EaseFactor, &uw.IntervalDays)
+		err := scanUserWord(rows, &uw)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user word: %w", err)
 		}
 		userWords = append(userWords, uw)
 	}
 
+	if err := s.attachNotes(user.ID, userWords); err != nil {
+		return nil, err
+	}
+
 	return userWords, nil
 }
 
-// GetUserWord retrieves a specific user word
-func (s *VocabularyService) GetUserWord(userID, wordID int64) (*models.UserWord, error) {
+// userWordColumns are the columns scanUserWord reads, from user_words uw joined to words w
+const userWordColumns = `uw.id, uw.user_id, uw.word_id, w.word, uw.card_type, uw.added_at, uw.status,
+		       uw.next_review_date, uw.ease_factor, uw.interval_days, uw.leitner_box,
+		       uw.learning_step, uw.relearning, uw.fsrs_stability, uw.fsrs_difficulty, uw.last_reviewed_at`
+
+// scanUserWord reads a row selected with userWordColumns
+func scanUserWord(row rowScanner, uw *models.UserWord) error {
+	var lastReviewed sql.NullTime
+	err := row.Scan(&uw.ID, &uw.UserID, &uw.WordID, &uw.Word, &uw.CardType, &uw.AddedAt,
+		&uw.Status, &uw.NextReviewDate, &uw.EaseFactor, &uw.IntervalDays, &uw.LeitnerBox,
+		&uw.LearningStep, &uw.Relearning, &uw.Stability, &uw.Difficulty, &lastReviewed)
+	if err != nil {
+		return err
+	}
+
+	if lastReviewed.Valid {
+		uw.LastReviewedAt = &lastReviewed.Time
+	}
+	if uw.Stability > 0 {
+		uw.Retrievability = fsrsRetrievability(daysSince(cardState(uw).LastReview, time.Now()), uw.Stability)
+	}
+	return nil
+}
+
+// GetUserWord retrieves one of a word's cards
+func (s *VocabularyService) GetUserWord(userID, wordID int64, cardType string) (*models.UserWord, error) {
 	uw := &models.UserWord{}
-	err := s.db.QueryRow(`
-		SELECT uw.id, uw.user_id, uw.word_id, w.word, uw.added_at, uw.status,
-		       uw.next_review_date, uw.ease_factor, uw.interval_days
+	err := scanUserWord(s.db.QueryRow(`
+		SELECT `+userWordColumns+`
 		FROM user_words uw
 		JOIN words w ON uw.word_id = w.id
-		WHERE uw.user_id = ? AND uw.word_id = ?
-	`, userID, wordID).Scan(&uw.ID, &uw.UserID, &uw.WordID, &uw.Word, &uw.AddedAt,
-		&uw.Status, &uw.NextReviewDate, &uw.EaseFactor, &uw.IntervalDays)
+		WHERE uw.user_id = ? AND uw.word_id = ? AND uw.card_type = ?
+	`, userID, wordID, cardType), uw)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user word not found")
@@ -146,14 +299,12 @@ func (s *VocabularyService) GetUserWord(userID, wordID int64) (*models.UserWord,
 // GetUserWordByID retrieves a user word by its ID
 func (s *VocabularyService) GetUserWordByID(id int64) (*models.UserWord, error) {
 	uw := &models.UserWord{}
-	err := s.db.QueryRow(`
-		SELECT uw.id, uw.user_id, uw.word_id, w.word, uw.added_at, uw.status,
-		       uw.next_review_date, uw.ease_factor, uw.interval_days
+	err := scanUserWord(s.db.QueryRow(`
+		SELECT `+userWordColumns+`
 		FROM user_words uw
 		JOIN words w ON uw.word_id = w.id
 		WHERE uw.id = ?
-	`, id).Scan(&uw.ID, &uw.UserID, &uw.WordID, &uw.Word, &uw.AddedAt,
-		&uw.Status, &uw.NextReviewDate, &uw.EaseFactor, &uw.IntervalDays)
+	`, id), uw)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user word not found")
@@ -164,3 +315,190 @@ func (s *VocabularyService) GetUserWordByID(id int64) (*models.UserWord, error)
 
 	return uw, nil
 }
+
+// GetNotes retrieves a user's notes for a word in their study list
+func (s *VocabularyService) GetNotes(username, wordStr string) (*models.WordNotes, error) {
+	user, word, err := s.getStudiedWord(username, wordStr)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.loadNotes(user.ID, word.ID)
+	if err != nil {
+		return nil, err
+	}
+	if notes[word.ID] == nil {
+		return &models.WordNotes{}, nil
+	}
+
+	return notes[word.ID], nil
+}
+
+// UpdateNotes replaces a user's notes, mnemonic, preferred definition and example sentences for a word
+func (s *VocabularyService) UpdateNotes(username, wordStr string, request models.WordNotesRequest) (*models.WordNotes, error) {
+	user, word, err := s.getStudiedWord(username, wordStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// The preferred definition must be one of this word's senses
+	var preferredID interface{}
+	if request.PreferredDefinitionID > 0 {
+		var ownerID int64
+		err := s.db.QueryRow(`
+			SELECT m.word_id FROM definitions d JOIN meanings m ON d.meaning_id = m.id WHERE d.id = ?
+		`, request.PreferredDefinitionID).Scan(&ownerID)
+		if err == sql.ErrNoRows || (err == nil && ownerID != word.ID) {
+			return nil, fmt.Errorf("definition not found")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get definition: %w", err)
+		}
+		preferredID = request.PreferredDefinitionID
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO user_word_notes (user_id, word_id, notes, mnemonic, preferred_definition_id, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, word_id) DO UPDATE SET
+			notes = excluded.notes,
+			mnemonic = excluded.mnemonic,
+			preferred_definition_id = excluded.preferred_definition_id,
+			updated_at = excluded.updated_at
+	`, user.ID, word.ID, strings.TrimSpace(request.Notes), strings.TrimSpace(request.Mnemonic), preferredID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to save notes: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM user_word_examples WHERE user_id = ? AND word_id = ?`, user.ID, word.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace examples: %w", err)
+	}
+
+	for _, sentence := range request.Examples {
+		if sentence = strings.TrimSpace(sentence); sentence == "" {
+			continue
+		}
+		_, err := tx.Exec(`
+			INSERT INTO user_word_examples (user_id, word_id, sentence, created_at) VALUES (?, ?, ?, ?)
+		`, user.ID, word.ID, sentence, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to save example: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetNotes(username, wordStr)
+}
+
+// getStudiedWord resolves a user and a word, requiring the word to be in the user's study list
+func (s *VocabularyService) getStudiedWord(username, wordStr string) (*models.User, *models.Word, error) {
+	user, err := s.userService.GetUser(username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	word, err := s.wordService.GetWord(strings.ToLower(strings.TrimSpace(wordStr)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("word not found: %w", err)
+	}
+
+	var studied bool
+	err = s.db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM user_words WHERE user_id = ? AND word_id = ?)
+	`, user.ID, word.ID).Scan(&studied)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user word: %w", err)
+	}
+	if !studied {
+		return nil, nil, fmt.Errorf("word not in user's study list")
+	}
+
+	return user, word, nil
+}
+
+// attachNotes fills in the user's notes on each word that has them
+func (s *VocabularyService) attachNotes(userID int64, userWords []models.UserWord) error {
+	if len(userWords) == 0 {
+		return nil
+	}
+
+	notes, err := s.loadNotes(userID, 0)
+	if err != nil {
+		return err
+	}
+
+	for i := range userWords {
+		userWords[i].Notes = notes[userWords[i].WordID]
+	}
+
+	return nil
+}
+
+// loadNotes retrieves a user's notes keyed by word ID, for one word or (wordID 0) all of them
+func (s *VocabularyService) loadNotes(userID, wordID int64) (map[int64]*models.WordNotes, error) {
+	filter := ""
+	args := []interface{}{userID}
+	if wordID > 0 {
+		filter = " AND n.word_id = ?"
+		args = append(args, wordID)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT n.word_id, n.notes, n.mnemonic, n.preferred_definition_id, d.definition, n.updated_at
+		FROM user_word_notes n
+		LEFT JOIN definitions d ON n.preferred_definition_id = d.id
+		WHERE n.user_id = ?`+filter, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := make(map[int64]*models.WordNotes)
+	for rows.Next() {
+		var id int64
+		var text, mnemonic, preferred sql.NullString
+		var preferredID sql.NullInt64
+		n := &models.WordNotes{}
+		if err := rows.Scan(&id, &text, &mnemonic, &preferredID, &preferred, &n.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notes: %w", err)
+		}
+		n.Notes = text.String
+		n.Mnemonic = mnemonic.String
+		n.PreferredDefinitionID = preferredID.Int64
+		n.PreferredDefinition = preferred.String
+		notes[id] = n
+	}
+	rows.Close()
+
+	exampleRows, err := s.db.Query(`
+		SELECT word_id, sentence FROM user_word_examples n
+		WHERE n.user_id = ?`+filter+` ORDER BY id ASC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get examples: %w", err)
+	}
+	defer exampleRows.Close()
+
+	for exampleRows.Next() {
+		var id int64
+		var sentence string
+		if err := exampleRows.Scan(&id, &sentence); err != nil {
+			return nil, fmt.Errorf("failed to scan example: %w", err)
+		}
+		if notes[id] == nil {
+			notes[id] = &models.WordNotes{}
+		}
+		notes[id].Examples = append(notes[id].Examples, sentence)
+	}
+
+	return notes, nil
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/words-api/words/pkg/dictionary"
 )
 
+// sourceDictionaryAPI tags rows cached from dictionaryapi.dev, distinguishing them from bulk imports
+const sourceDictionaryAPI = "dictionaryapi"
+
 // WordService handles business logic for word operations
 type WordService struct {
 	db       *sql.DB
@@ -55,15 +58,26 @@ func (s *WordService) GetWord(word string) (*models.Word, error) {
 	return nil, fmt.Errorf("failed to retrieve word: %w", err)
 }
 
+// GetWordVersion returns the ID and last update time of a locally stored word
+// without loading the full entry. Returns sql.ErrNoRows if the word is not cached.
+func (s *WordService) GetWordVersion(word string) (int64, time.Time, error) {
+	var id int64
+	var updatedAt time.Time
+	err := s.db.QueryRow(`
+		SELECT id, updated_at FROM words WHERE word = ?
+	`, strings.ToLower(word)).Scan(&id, &updatedAt)
+	return id, updatedAt, err
+}
+
 // getFromDB retrieves a word from the local database
 func (s *WordService) getFromDB(word string) (*models.Word, error) {
 	w := &models.Word{}
 
 	// Get word basic info
 	err := s.db.QueryRow(`
-		SELECT id, word, phonetic, created_at, updated_at
+		SELECT id, word, phonetic, COALESCE(wordset_id, ''), created_at, updated_at
 		FROM words WHERE word = ?
-	`, word).Scan(&w.ID, &w.Word, &w.Phonetic, &w.CreatedAt, &w.UpdatedAt)
+	`, word).Scan(&w.ID, &w.Word, &w.Phonetic, &w.WordsetID, &w.CreatedAt, &w.UpdatedAt)
 
 	if err != nil {
 		return nil, err
@@ -105,7 +119,7 @@ func (s *WordService) getFromDB(word string) (*models.Word, error) {
 
 		// Get definitions for this meaning
 		defRows, err := s.db.Query(`
-			SELECT id, definition, example FROM definitions WHERE meaning_id = ?
+			SELECT id, definition, example, COALESCE(external_id, '') FROM definitions WHERE meaning_id = ?
 		`, m.ID)
 		if err != nil {
 			return nil, err
@@ -113,7 +127,7 @@ func (s *WordService) getFromDB(word string) (*models.Word, error) {
 
 		for defRows.Next() {
 			var d models.Definition
-			if err := defRows.Scan(&d.ID, &d.Definition, &d.Example); err != nil {
+			if err := defRows.Scan(&d.ID, &d.Definition, &d.Example, &d.ExternalID); err != nil {
 				defRows.Close()
 				return nil, err
 			}
@@ -151,6 +165,23 @@ func (s *WordService) getFromDB(word string) (*models.Word, error) {
 		w.SourceUrls = append(w.SourceUrls, url)
 	}
 
+	// Get credits
+	creditRows, err := s.db.Query(`
+		SELECT name, role, source FROM word_credits WHERE word_id = ? ORDER BY role DESC, id
+	`, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer creditRows.Close()
+
+	for creditRows.Next() {
+		var c models.Credit
+		if err := creditRows.Scan(&c.Name, &c.Role, &c.Source); err != nil {
+			return nil, err
+		}
+		w.Credits = append(w.Credits, c)
+	}
+
 	return w, nil
 }
 
@@ -179,8 +210,8 @@ func (s *WordService) saveToDB(word *models.Word) error {
 	// Insert phonetics
 	for _, p := range word.Phonetics {
 		_, err := tx.Exec(`
-			INSERT INTO phonetics (word_id, text, audio) VALUES (?, ?, ?)
-		`, wordID, p.Text, p.Audio)
+			INSERT INTO phonetics (word_id, text, audio, source) VALUES (?, ?, ?, ?)
+		`, wordID, p.Text, p.Audio, sourceDictionaryAPI)
 		if err != nil {
 			return err
 		}
@@ -189,8 +220,8 @@ func (s *WordService) saveToDB(word *models.Word) error {
 	// Insert meanings and definitions
 	for _, m := range word.Meanings {
 		result, err := tx.Exec(`
-			INSERT INTO meanings (word_id, part_of_speech) VALUES (?, ?)
-		`, wordID, m.PartOfSpeech)
+			INSERT INTO meanings (word_id, part_of_speech, source) VALUES (?, ?, ?)
+		`, wordID, m.PartOfSpeech, sourceDictionaryAPI)
 		if err != nil {
 			return err
 		}
@@ -203,8 +234,8 @@ func (s *WordService) saveToDB(word *models.Word) error {
 		// Insert meaning-level synonyms and antonyms
 		for _, syn := range m.Synonyms {
 			_, err := tx.Exec(`
-				INSERT INTO synonyms (meaning_id, synonym) VALUES (?, ?)
-			`, meaningID, syn)
+				INSERT INTO synonyms (meaning_id, synonym, source) VALUES (?, ?, ?)
+			`, meaningID, syn, sourceDictionaryAPI)
 			if err != nil {
 				return err
 			}
@@ -212,8 +243,8 @@ func (s *WordService) saveToDB(word *models.Word) error {
 
 		for _, ant := range m.Antonyms {
 			_, err := tx.Exec(`
-				INSERT INTO antonyms (meaning_id, antonym) VALUES (?, ?)
-			`, meaningID, ant)
+				INSERT INTO antonyms (meaning_id, antonym, source) VALUES (?, ?, ?)
+			`, meaningID, ant, sourceDictionaryAPI)
 			if err != nil {
 				return err
 			}
@@ -222,8 +253,8 @@ func (s *WordService) saveToDB(word *models.Word) error {
 		// Insert definitions
 		for _, d := range m.Definitions {
 			result, err := tx.Exec(`
-				INSERT INTO definitions (meaning_id, definition, example) VALUES (?, ?, ?)
-			`, meaningID, d.Definition, d.Example)
+				INSERT INTO definitions (meaning_id, definition, example, source) VALUES (?, ?, ?, ?)
+			`, meaningID, d.Definition, d.Example, sourceDictionaryAPI)
 			if err != nil {
 				return err
 			}
@@ -236,8 +267,8 @@ func (s *WordService) saveToDB(word *models.Word) error {
 			// Insert definition-level synonyms and antonyms
 			for _, syn := range d.Synonyms {
 				_, err := tx.Exec(`
-					INSERT INTO synonyms (definition_id, synonym) VALUES (?, ?)
-				`, definitionID, syn)
+					INSERT INTO synonyms (definition_id, synonym, source) VALUES (?, ?, ?)
+				`, definitionID, syn, sourceDictionaryAPI)
 				if err != nil {
 					return err
 				}
@@ -245,8 +276,8 @@ func (s *WordService) saveToDB(word *models.Word) error {
 
 			for _, ant := range d.Antonyms {
 				_, err := tx.Exec(`
-					INSERT INTO antonyms (definition_id, antonym) VALUES (?, ?)
-				`, definitionID, ant)
+					INSERT INTO antonyms (definition_id, antonym, source) VALUES (?, ?, ?)
+				`, definitionID, ant, sourceDictionaryAPI)
 				if err != nil {
 					return err
 				}
@@ -257,8 +288,8 @@ func (s *WordService) saveToDB(word *models.Word) error {
 	// Insert source URLs
 	for _, url := range word.SourceUrls {
 		_, err := tx.Exec(`
-			INSERT INTO source_urls (word_id, url) VALUES (?, ?)
-		`, wordID, url)
+			INSERT INTO source_urls (word_id, url, source) VALUES (?, ?, ?)
+		`, wordID, url, sourceDictionaryAPI)
 		if err != nil {
 			return err
 		}
@@ -56,10 +56,11 @@ func (c *Client) FetchWord(word string) (*models.Word, error) {
 	}
 
 	// Convert API response to our Word model
-	return convertAPIResponse(apiResp[0]), nil
+	return ConvertAPIResponse(apiResp[0]), nil
 }
 
-func convertAPIResponse(apiWord struct {
+// ConvertAPIResponse converts one dictionaryapi.dev entry to our Word model
+func ConvertAPIResponse(apiWord struct {
 	Word      string `json:"word"`
 	Phonetic  string `json:"phonetic"`
 	Phonetics []struct {
@@ -40,7 +40,8 @@ function Review({ user, onComplete }) {
   const handleReview = async (quality) => {
     setReviewing(true);
     try {
-      await api.submitReview(dueWords[currentIndex].word, quality);
+      const card = dueWords[currentIndex];
+      await api.submitReview(card.word, quality, card.card_type);
 
       // Move to next word
       if (currentIndex + 1 < dueWords.length) {
@@ -97,7 +98,7 @@ function Review({ user, onComplete }) {
     <div className="review-container">
       <div className="content-card" style={{ marginBottom: '20px', textAlign: 'center' }}>
         <p style={{ color: '#666', margin: 0 }}>
-          Card {currentIndex + 1} of {dueWords.length}
+          Card {currentIndex + 1} of {dueWords.length} ({currentWord.card_type})
         </p>
       </div>
 
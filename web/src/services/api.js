@@ -92,10 +92,10 @@ class ApiService {
     return this.request('/review');
   }
 
-  async submitReview(word, quality) {
+  async submitReview(word, quality, cardType) {
     return this.request(`/review/${word}`, {
       method: 'POST',
-      body: JSON.stringify({ quality }),
+      body: JSON.stringify({ quality, card_type: cardType }),
     });
   }
 